@@ -0,0 +1,156 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const aliasesEndpoint = "aliases"
+
+// Alias is a virtual collection name that points to a real
+// collection, it allows swapping collections without downtime.
+type Alias struct {
+	Name           string `json:"name"`
+	CollectionName string `json:"collection_name"`
+}
+
+// UpsertAlias creates the alias or updates it to point to the
+// target collection. It returns ErrAliasTargetNotFound when the target
+// collection doesn't exist, so no dangling alias is created.
+func (c *Client) UpsertAlias(aliasName, targetCollection string, opts ...CallOption) (*Alias, error) {
+	method := http.MethodPut
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		aliasesEndpoint,
+		aliasName,
+	)
+	aliasJSON, _ := json.Marshal(Alias{CollectionName: targetCollection})
+	resp, err := c.apiCall(method, url, aliasJSON, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAliasTargetNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var alias Alias
+	if err := json.NewDecoder(resp.Body).Decode(&alias); err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+// RetrieveAlias retrieves a single alias by its name.
+func (c *Client) RetrieveAlias(aliasName string, opts ...CallOption) (*Alias, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		aliasesEndpoint,
+		aliasName,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAliasNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var alias Alias
+	if err := json.NewDecoder(resp.Body).Decode(&alias); err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+// RetrieveAliases get all aliases from Typesense.
+func (c *Client) RetrieveAliases(opts ...CallOption) ([]*Alias, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		aliasesEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	type aliasesResponse struct {
+		Aliases []*Alias `json:"aliases"`
+	}
+	var aliases aliasesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&aliases); err != nil {
+		return nil, err
+	}
+	return aliases.Aliases, nil
+}
+
+// AliasesForCollection returns the aliases pointing to the collection,
+// e.g. to check that no alias targets a collection before deleting it.
+func (c *Client) AliasesForCollection(collectionName string, opts ...CallOption) ([]*Alias, error) {
+	aliases, err := c.RetrieveAliases(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var matching []*Alias
+	for _, alias := range aliases {
+		if alias.CollectionName == collectionName {
+			matching = append(matching, alias)
+		}
+	}
+	return matching, nil
+}
+
+// DeleteAlias deletes an alias by its name, the collection it
+// points to is not deleted.
+func (c *Client) DeleteAlias(aliasName string, opts ...CallOption) (*Alias, error) {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		aliasesEndpoint,
+		aliasName,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAliasNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var alias Alias
+	if err := json.NewDecoder(resp.Body).Decode(&alias); err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
@@ -0,0 +1,133 @@
+package typesense
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpsertAlias(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodPut, req.Method)
+		}
+		var alias Alias
+		if err := json.NewDecoder(req.Body).Decode(&alias); err != nil {
+			t.Errorf("Expected to decode the request body, received %v", err)
+		}
+		if alias.CollectionName != "companies_june11" {
+			t.Errorf("Expected to receive collection %s, received %s", "companies_june11", alias.CollectionName)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies", "collection_name": "companies_june11"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	alias, err := client.UpsertAlias("companies", "companies_june11")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if alias.Name != "companies" || alias.CollectionName != "companies_june11" {
+		t.Errorf("Expected to receive alias companies to companies_june11, received %+v", alias)
+	}
+}
+
+func TestUpsertAlias_targetNotFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Collection not found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.UpsertAlias("companies", "companies_missing"); err != ErrAliasTargetNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrAliasTargetNotFound, err)
+	}
+}
+
+func TestRetrieveAlias_notFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.RetrieveAlias("companies"); err != ErrAliasNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrAliasNotFound, err)
+	}
+}
+
+func TestRetrieveAliases(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"aliases": [{"name": "companies", "collection_name": "companies_june11"}, {"name": "books", "collection_name": "books_v2"}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	aliases, err := client.RetrieveAliases()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if len(aliases) != 2 {
+		t.Errorf("Expected to receive %d aliases, received %d", 2, len(aliases))
+	}
+}
+
+func TestDeleteAlias_unauthorized(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.DeleteAlias("companies"); err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+}
+
+func TestAliasesForCollection(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"aliases": [
+				{"name": "companies", "collection_name": "companies_june11"},
+				{"name": "books", "collection_name": "books_v2"},
+				{"name": "companies_latest", "collection_name": "companies_june11"}
+			]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	aliases, err := client.AliasesForCollection("companies_june11")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if len(aliases) != 2 || aliases[0].Name != "companies" || aliases[1].Name != "companies_latest" {
+		t.Errorf("Expected to receive the aliases companies and companies_latest, received %v", aliases)
+	}
+}
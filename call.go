@@ -0,0 +1,252 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CallOption configures a single API call, overriding the client
+// defaults only for that call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	ctx     context.Context
+	timeout time.Duration
+
+	// query are the query parameters appended to the URL of the call.
+	query url.Values
+
+	// headers are the headers added to the requests of the call.
+	headers http.Header
+
+	// apiKey overrides the API key of the client for the call.
+	apiKey string
+
+	// unlimitedResponse exempts the call from the maximum response
+	// size of the client, used by the streaming methods.
+	unlimitedResponse bool
+
+	// compressibleBody marks the body of the call as compressible with
+	// the request compression of the client, used by the document
+	// writes and the imports.
+	compressibleBody bool
+
+	// bodyReader is streamed as the body of the call instead of the
+	// body bytes, the call is never retried nor compressed since the
+	// reader can only be read once.
+	bodyReader io.Reader
+
+	// err is the error of an invalid option, the call fails with it
+	// without making any request.
+	err error
+}
+
+// WithContext sets the context of a single call, cancelling the
+// context cancels the call and the call returns the error of the
+// context. The client or call timeout still applies on top of any
+// deadline of the context. Every method of the client accepts it.
+func WithContext(ctx context.Context) CallOption {
+	return func(o *callOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithCallTimeout sets a timeout for a single call, overriding the
+// client default timeout. It is useful for long running operations
+// like big imports or exports that need a bigger budget than the
+// other calls.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRemoteEmbeddingTimeout sets the timeout, in milliseconds, of
+// the requests to the remote embedding model when indexing, importing
+// or searching documents of a collection with auto-embedding fields.
+func WithRemoteEmbeddingTimeout(timeoutMs int) CallOption {
+	return withQueryParameter("remote_embedding_timeout_ms", strconv.Itoa(timeoutMs))
+}
+
+// WithRemoteEmbeddingNumTries sets the number of tries of the requests
+// to the remote embedding model when indexing, importing or searching
+// documents of a collection with auto-embedding fields, so slow
+// embedding providers don't fail the import.
+func WithRemoteEmbeddingNumTries(numTries int) CallOption {
+	return withQueryParameter("remote_embedding_num_tries", strconv.Itoa(numTries))
+}
+
+// WithHeader sets a header of the requests of a single call, e.g. a
+// tracing id, overriding the header of the same name set with
+// WithDefaultHeaders. The API key header can't be changed this way,
+// see WithAPIKey.
+func WithHeader(name, value string) CallOption {
+	return func(o *callOptions) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		o.headers.Set(name, value)
+	}
+}
+
+// WithAPIKey authenticates a single call with the given API key instead
+// of the API key of the client, e.g. to search with a tenant scoped key
+// generated with GenerateScopedSearchKey without a client per tenant.
+// The results of the searches made with it are not stored in nor served
+// from the result cache of the client, see WithResultCache.
+func WithAPIKey(apiKey string) CallOption {
+	return func(o *callOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithDirtyValues sets how Typesense handles the values of the
+// documents that don't match the type of their field, e.g. a number
+// sent as a string, when a document is created, upserted or imported.
+// The mode is one of coerce_or_reject, coerce_or_drop, drop or reject,
+// the call fails with ErrInvalidDirtyValues otherwise.
+func WithDirtyValues(mode string) CallOption {
+	if !validDirtyValues(mode) {
+		return func(o *callOptions) {
+			o.err = fmt.Errorf("%w: %q", ErrInvalidDirtyValues, mode)
+		}
+	}
+	return withQueryParameter("dirty_values", mode)
+}
+
+func validDirtyValues(mode string) bool {
+	switch mode {
+	case "coerce_or_reject", "coerce_or_drop", "drop", "reject":
+		return true
+	default:
+		return false
+	}
+}
+
+// WithIncludeFields limits the fields of the documents returned by a
+// single call, e.g. RetrieveDocument, to the given fields. When it is
+// used with WithExcludeFields, Typesense applies the exclusion after the
+// inclusion.
+func WithIncludeFields(fields ...string) CallOption {
+	return withQueryParameter("include_fields", strings.Join(fields, ","))
+}
+
+// WithExcludeFields leaves the given fields out of the documents
+// returned by a single call, e.g. RetrieveDocument.
+func WithExcludeFields(fields ...string) CallOption {
+	return withQueryParameter("exclude_fields", strings.Join(fields, ","))
+}
+
+// WithFilterBy exports only the documents matching the filter with
+// ExportDocuments, e.g. user_id:=42.
+func WithFilterBy(filter string) CallOption {
+	return withQueryParameter("filter_by", filter)
+}
+
+// withQueryParameter appends a query parameter to the URL of the call.
+func withQueryParameter(name, value string) CallOption {
+	return func(o *callOptions) {
+		if o.query == nil {
+			o.query = url.Values{}
+		}
+		o.query.Set(name, value)
+	}
+}
+
+// withUnlimitedResponse exempts a call from the maximum response size
+// of the client.
+func withUnlimitedResponse() CallOption {
+	return func(o *callOptions) {
+		o.unlimitedResponse = true
+	}
+}
+
+func (c *Client) newCallOptions(opts []CallOption) *callOptions {
+	options := callOptions{
+		ctx:     context.Background(),
+		timeout: c.timeout,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &options
+}
+
+// context returns the context the call must run with. The returned
+// cancel function must only be called after the response body was
+// consumed.
+func (o *callOptions) context() (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(o.ctx, o.timeout)
+	}
+	return context.WithCancel(o.ctx)
+}
+
+// cancelReadCloser cancels the context of the call when the response
+// body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (rc cancelReadCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.cancel()
+	return err
+}
+
+// limitedReadCloser fails the reads with ErrResponseTooLarge once more
+// than the remaining bytes were read from the response body.
+type limitedReadCloser struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (rc *limitedReadCloser) Read(p []byte) (int, error) {
+	if rc.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > rc.remaining+1 {
+		p = p[:rc.remaining+1]
+	}
+	n, err := rc.ReadCloser.Read(p)
+	rc.remaining -= int64(n)
+	if rc.remaining < 0 {
+		return n + int(rc.remaining), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// withQuery returns the URL with the query parameters of the call
+// appended.
+func (o *callOptions) withQuery(rawURL string) string {
+	if len(o.query) == 0 {
+		return rawURL
+	}
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + o.query.Encode()
+}
+
+// withBodyReader streams the reader as the body of a call.
+func withBodyReader(r io.Reader) CallOption {
+	return func(o *callOptions) {
+		o.bodyReader = r
+	}
+}
+
+// withCompressibleBody marks the body of a call as compressible, see
+// WithRequestCompression.
+func withCompressibleBody() CallOption {
+	return func(o *callOptions) {
+		o.compressibleBody = true
+	}
+}
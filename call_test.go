@@ -0,0 +1,147 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCallTimeout(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(time.Second):
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+			}, nil
+		}
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+		timeout:    time.Hour,
+	}
+	start := time.Now()
+	_, err := client.Search(collectionNameTest, SearchParameters{Q: "query", QueryBy: []string{"title"}}, WithCallTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected to receive error %v, received %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Expected the call timeout to fire before the client timeout, took %v", elapsed)
+	}
+}
+
+func TestWithCallTimeout_clientDefault(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		deadline, ok := req.Context().Deadline()
+		if !ok {
+			t.Errorf("Expected the request to have a deadline")
+		} else if remaining := time.Until(deadline); remaining < time.Minute {
+			t.Errorf("Expected the client default timeout to be applied, remaining %v", remaining)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+		timeout:    time.Hour,
+	}
+	if _, err := client.Search(collectionNameTest, SearchParameters{Q: "query", QueryBy: []string{"title"}}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestWithContext_cancelled(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: req.Context().Err()}
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.CreateCollection(testCollectionSchema, WithContext(ctx))
+	if err != context.Canceled {
+		t.Errorf("Expected to receive error %v, received %v", context.Canceled, err)
+	}
+	_, err = client.ImportDocuments(collectionNameTest, []interface{}{testDocument}, "create", WithContext(ctx))
+	if err != context.Canceled {
+		t.Errorf("Expected to receive error %v, received %v", context.Canceled, err)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var header http.Header
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	WithDefaultHeaders(map[string]string{
+		"X-Tenant":            "acme",
+		"X-Trace-Id":          "default",
+		"x-typesense-api-key": "other-key",
+	})(&client)
+	params := SearchParameters{Q: "query", QueryBy: []string{"title"}}
+	if _, err := client.Search(collectionNameTest, params, WithHeader("X-Trace-Id", "abc123")); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if header.Get("X-Tenant") != "acme" {
+		t.Errorf("Expected to receive the default header %q, received %q", "acme", header.Get("X-Tenant"))
+	}
+	if header.Get("X-Trace-Id") != "abc123" {
+		t.Errorf("Expected the call header to override the default header, received %q", header.Get("X-Trace-Id"))
+	}
+	if values := header.Values(defaultHeaderKey); len(values) != 1 || values[0] != testMasterNode.APIKey {
+		t.Errorf("Expected to receive the API key %q, received %v", testMasterNode.APIKey, values)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	var apiKeys []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		apiKeys = append(apiKeys, req.Header.Get(defaultHeaderKey))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	cache := &testResultCache{results: make(map[string]*SearchResponse)}
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		resultCache: cache,
+	}
+	params := SearchParameters{Q: "query", QueryBy: []string{"title"}}
+	if _, err := client.Search(collectionNameTest, params, WithAPIKey("scoped-key")); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if _, err := client.Search(collectionNameTest, params); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"scoped-key", testMasterNode.APIKey}; !reflect.DeepEqual(apiKeys, expected) {
+		t.Errorf("Expected to receive the API keys %v, received %v", expected, apiKeys)
+	}
+	if len(cache.results) != 1 {
+		t.Errorf("Expected to cache only the search made with the client key, cached %v", len(cache.results))
+	}
+}
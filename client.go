@@ -2,23 +2,297 @@ package typesense
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 const defaultHeaderKey = "X-TYPESENSE-API-KEY"
 
+// defaultTimeout is the timeout of each call of a client created
+// with NewClient, unless it is changed with WithTimeout.
+const defaultTimeout = 30 * time.Second
+
+// defaultMaxIdleConns is the number of idle connections kept by the
+// default HTTP client for each node.
+const defaultMaxIdleConns = 64
+
+// defaultMaxConcurrentRequests is the maximum number of requests made
+// concurrently by the methods that fan out to several requests, unless
+// it is changed with WithMaxConcurrentRequests.
+const defaultMaxConcurrentRequests = 4
+
 type httpClient interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
 // Client is the client to communicate with the Typesense API.
 type Client struct {
+	// retries and rateLimitWaits are updated atomically, they are the
+	// first fields to keep them 64-bit aligned.
+	retries        int64
+	rateLimitWaits int64
+
 	httpClient       httpClient
 	masterNode       *Node
 	readReplicaNodes []*Node
+	timeout          time.Duration
+	maxResponseBytes int64
+	retryMaxAttempts int
+	retryBaseDelay   time.Duration
+	highlightV1      *bool
+	defaultQueryBy   []string
+	importBatchSize  int
+	maxConcurrent    int
+	defaultHeaders   http.Header
+	resultCache      ResultCache
+
+	fieldNameTransformer func(string) string
+	nullHandling         NullHandling
+	idField              string
+	requestCompression   bool
+	compressionThreshold int
+
+	onRequest  func(RequestInfo)
+	onResponse func(ResponseInfo)
+	logger     Logger
+
+	// lastNode is the node that served the last request, see
+	// LastUsedNode.
+	lastNode atomic.Pointer[Node]
+
+	// configErr is the error of a client option that couldn't be
+	// applied, it is returned by every call of the client.
+	configErr error
+}
+
+// ClientOption configures optional behaviors of the client.
+type ClientOption func(*Client)
+
+// WithReadReplicas sets the read replica nodes of the client.
+func WithReadReplicas(replicaNodes ...*Node) ClientOption {
+	return func(c *Client) {
+		c.readReplicaNodes = replicaNodes
+	}
+}
+
+// WithTimeout sets the default timeout of the calls of the client,
+// it can be overridden for a single call with WithCallTimeout. A zero
+// timeout disables it.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to make the requests to
+// Typesense, replacing the default client. Give it before WithTLSConfig
+// or WithInsecureSkipVerify for them to apply to its transport.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport of the HTTP client used to make
+// the requests to Typesense, e.g. to use a proxy or custom TLS
+// settings. It replaces the HTTP client of the client. Give it before
+// WithTLSConfig or WithInsecureSkipVerify for them to apply to it.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the transport of the
+// client, e.g. to trust the CA of a cluster with a self-signed
+// certificate with RootCAs or to authenticate with a client
+// certificate. It keeps the other settings of the transport. A nil
+// config is ignored.
+//
+// The options are applied in order: WithTLSConfig changes the transport
+// of a WithHTTPClient or WithTransport option given before it, while a
+// WithHTTPClient or WithTransport option given after it replaces the
+// transport and its TLS configuration. The TLS configuration can only
+// be applied to an *http.Transport, with another transport the calls of
+// the client fail with ErrUnsupportedTransport.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		if config == nil {
+			return
+		}
+		c.updateTLSConfig(func(tlsConfig *tls.Config) {
+			*tlsConfig = *config.Clone()
+		})
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the certificate
+// of the nodes. It is meant for development clusters only: the client
+// accepts any certificate, so anyone able to intercept the connection
+// can impersonate Typesense and read the API key and the documents.
+// Prefer WithTLSConfig with the CA of the cluster in RootCAs. Nothing
+// is logged when it is enabled. It is applied in order with the other
+// options like WithTLSConfig.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *Client) {
+		c.updateTLSConfig(func(tlsConfig *tls.Config) {
+			tlsConfig.InsecureSkipVerify = true
+		})
+	}
+}
+
+// updateTLSConfig updates a copy of the TLS configuration of the
+// transport of the client and replaces the HTTP client with a copy
+// using it, so an HTTP client or transport shared with other code is
+// not changed. HTTP clients without a transport get the default one.
+// Other HTTP clients and transports are kept unchanged and the calls
+// of the client fail with ErrUnsupportedTransport, so the requests are
+// never made without the TLS configuration.
+func (c *Client) updateTLSConfig(update func(*tls.Config)) {
+	client := &http.Client{}
+	if c.httpClient != nil {
+		httpClient, ok := c.httpClient.(*http.Client)
+		if !ok {
+			c.configErr = fmt.Errorf("%w: the HTTP client is a %T", ErrUnsupportedTransport, c.httpClient)
+			return
+		}
+		*client = *httpClient
+	}
+	var transport *http.Transport
+	switch clientTransport := client.Transport.(type) {
+	case nil:
+		transport = newDefaultTransport()
+	case *http.Transport:
+		transport = clientTransport.Clone()
+	default:
+		c.configErr = fmt.Errorf("%w: the transport is a %T", ErrUnsupportedTransport, clientTransport)
+		return
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	update(transport.TLSClientConfig)
+	client.Transport = transport
+	c.httpClient = client
+}
+
+// WithMaxConcurrentRequests sets the maximum number of requests made
+// concurrently by the methods that fan out to several requests, e.g.
+// UpsertOverrides, so a small node is not overwhelmed. Default value
+// is 4.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *Client) {
+		c.maxConcurrent = n
+	}
+}
+
+// maxConcurrentRequests returns the maximum number of requests made
+// concurrently by the methods that fan out to several requests.
+func (c *Client) maxConcurrentRequests() int {
+	if c.maxConcurrent <= 0 {
+		return defaultMaxConcurrentRequests
+	}
+	return c.maxConcurrent
+}
+
+// WithHighlightV1 sets the default of EnableHighlightV1 for all the
+// searches of the client, a search that sets EnableHighlightV1 itself
+// overrides it. It helps to standardize the highlight format across an
+// application while migrating from the v1 format.
+func WithHighlightV1(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.highlightV1 = &enabled
+	}
+}
+
+// WithDefaultQueryBy sets the fields searched by the searches of the
+// client that don't set QueryBy, so ErrQueryByRequired is only
+// returned when neither the search nor the client set them.
+func WithDefaultQueryBy(fields ...string) ClientOption {
+	return func(c *Client) {
+		c.defaultQueryBy = fields
+	}
+}
+
+// WithFieldNameTransformer sets a function to transform the struct field
+// names of the documents into the Typesense field names when marshalling
+// documents, e.g. converting Go field names into snake_case. Fields with
+// an explicit json tag name are not transformed, tags always win. Map
+// keys are not transformed either.
+func WithFieldNameTransformer(transformer func(string) string) ClientOption {
+	return func(c *Client) {
+		c.fieldNameTransformer = transformer
+	}
+}
+
+// WithIDField sets the field whose value is copied into the id of the
+// documents that have no id when they are indexed, created, upserted or
+// imported, for data models with their primary key in another field.
+// Numeric values are formatted as strings. Writing a document without
+// the field fails with ErrIDFieldNotFound. The partial updates of
+// UpdateDocument and ConditionalUpdateDocument are sent as they are.
+func WithIDField(fieldName string) ClientOption {
+	return func(c *Client) {
+		c.idField = fieldName
+	}
+}
+
+// WithRequestCompression gzip compresses the bodies of the imports and
+// the document writes that have at least threshold bytes, streaming
+// them through the compressor with Content-Encoding gzip. It saves
+// bandwidth on big imports when the network is the bottleneck, the
+// other requests are not compressed.
+func WithRequestCompression(threshold int) ClientOption {
+	return func(c *Client) {
+		c.requestCompression = true
+		c.compressionThreshold = threshold
+	}
+}
+
+// WithNullHandling sets how the empty values of the documents are sent
+// when they are indexed or imported, SendNulls by default. Use OmitEmpty
+// when the empty fields are optional in the schema and CoerceToDefault
+// when the fields are required but the structs may leave them unset.
+func WithNullHandling(mode NullHandling) ClientOption {
+	return func(c *Client) {
+		c.nullHandling = mode
+	}
+}
+
+// WithMaxResponseBytes limits the size of the response bodies read by
+// the client to n bytes, reading a bigger response fails with
+// ErrResponseTooLarge. It protects the memory of the application from
+// a misbehaving server or an accidentally huge response. The streaming
+// methods, ExportDocuments and the imports, are not limited.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithDefaultHeaders sets headers sent with every request of the
+// client, e.g. the tracing or tenant routing headers required by a
+// proxy in front of Typesense. Headers set with WithHeader for a
+// single call take precedence. The API key header can't be changed
+// this way, it is always the key of the node.
+func WithDefaultHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.defaultHeaders = http.Header{}
+		for name, value := range headers {
+			c.defaultHeaders.Set(name, value)
+		}
+	}
 }
 
 // Node is a Typesense node, either the master or a read replica.
@@ -36,76 +310,392 @@ type Node struct {
 	APIKey string `json:"apiKey"`
 }
 
+// LastUsedNode returns the node that served the last request of the
+// client, e.g. to correlate a slow call with an overloaded node, or nil
+// before the first response. With concurrent calls the last request
+// may be of another call, use the URL of the ResponseInfo passed to the
+// WithOnResponse hook to know the node of each request.
+func (c *Client) LastUsedNode() *Node {
+	return c.lastNode.Load()
+}
+
+// nodeForURL returns the node of the client the URL points to, or a
+// node without API key made from the URL when it is of none of them.
+func (c *Client) nodeForURL(u *url.URL) *Node {
+	for _, node := range append([]*Node{c.masterNode}, c.readReplicaNodes...) {
+		if node != nil && node.Protocol == u.Scheme && node.Host == u.Hostname() && node.Port == u.Port() {
+			return node
+		}
+	}
+	return &Node{Host: u.Hostname(), Port: u.Port(), Protocol: u.Scheme}
+}
+
 // APIResponse is the default API message response.
 type APIResponse struct {
 	Message string `json:"message"`
 }
 
-// NewClient configures a client using the master node and timeout
-// seconds.
-func NewClient(masterNode *Node, timeoutSeconds int, replicaNodes ...*Node) *Client {
+// NewClient configures a client using the master node. By default the
+// client has a timeout of 30 seconds for each call and pools the
+// connections to the node, the defaults can be changed with the
+// client options.
+func NewClient(masterNode *Node, opts ...ClientOption) *Client {
 	client := Client{
-		httpClient: &http.Client{
-			Timeout: time.Duration(time.Second * time.Duration(timeoutSeconds)),
-		},
-		masterNode:       masterNode,
-		readReplicaNodes: replicaNodes,
+		httpClient: &http.Client{Transport: newDefaultTransport()},
+		masterNode: masterNode,
+		timeout:    defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(&client)
 	}
 	return &client
 }
 
+// startupHealthCheckTimeout is the timeout of the health check of each
+// node made by NewClientWithHealthCheck.
+const startupHealthCheckTimeout = 5 * time.Second
+
+// NewClientWithHealthCheck configures a client like NewClient and
+// checks the health of its nodes before returning it, so a client
+// pointed at an unreachable node fails at startup instead of at its
+// first call. The master node and the read replicas are checked in
+// order and the first healthy one becomes the master node of the
+// client. It returns an error wrapping ErrConnNotReady when none of
+// them is healthy.
+func NewClientWithHealthCheck(masterNode *Node, opts ...ClientOption) (*Client, error) {
+	client := NewClient(masterNode, opts...)
+	nodes := append([]*Node{masterNode}, client.readReplicaNodes...)
+	var failures []string
+	for _, node := range nodes {
+		healthy, err := client.nodeHealth(node, WithCallTimeout(startupHealthCheckTimeout))
+		if err == nil && healthy {
+			client.masterNode = node
+			return client, nil
+		}
+		if err == nil {
+			err = errors.New("not healthy")
+		}
+		failures = append(failures, fmt.Sprintf("%s:%s: %v", node.Host, node.Port, err))
+	}
+	return nil, fmt.Errorf("%w: %s", ErrConnNotReady, strings.Join(failures, "; "))
+}
+
+// newDefaultTransport returns the transport of the default HTTP
+// client, it keeps more idle connections to the node than the
+// standard transport since all requests go to the same few hosts.
+func newDefaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = defaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConns
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
 // Ping checks if the client has a connection with the Typesense API.
-func (c *Client) Ping() error {
-	if ok := c.Health(); !ok {
+func (c *Client) Ping(opts ...CallOption) error {
+	if ok, err := c.Health(opts...); err != nil || !ok {
 		return ErrConnNotReady
 	}
 	return nil
 }
 
-// DebugInfo retrieves the debug information from the Typesense API.
-func (c *Client) DebugInfo() (string, error) {
+// DebugResponse is the debug information of a Typesense node.
+type DebugResponse struct {
+	// Version is the version of Typesense the node runs.
+	Version string `json:"version"`
+
+	// State is the raft state of the node, 1 for the leader and 4 for
+	// a follower.
+	State int `json:"state"`
+}
+
+// Debug retrieves the debug information of the node from the Typesense
+// API, e.g. to check that all the nodes run the same version after a
+// rolling upgrade.
+func (c *Client) Debug(opts ...CallOption) (*DebugResponse, error) {
 	method := http.MethodGet
 	url := fmt.Sprintf("%s://%s:%s/debug", c.masterNode.Protocol, c.masterNode.Host, c.masterNode.Port)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
-	type debugResponse struct {
-		Version string `json:"version"`
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
 	}
-	var debug debugResponse
+	var debug DebugResponse
 	if err := json.NewDecoder(resp.Body).Decode(&debug); err != nil {
+		return nil, err
+	}
+	return &debug, nil
+}
+
+// DebugInfo retrieves the version of Typesense from the debug
+// information of the Typesense API, see Debug.
+func (c *Client) DebugInfo(opts ...CallOption) (string, error) {
+	debug, err := c.Debug(opts...)
+	if err != nil {
 		return "", err
 	}
 	return debug.Version, nil
 }
 
-// Health checks the health information from the Typesense API.
-func (c *Client) Health() bool {
+// Health checks the health information from the Typesense API. A
+// node that answers with an error status or that reports it is not
+// ok is unhealthy without an error, so callers can poll it, only
+// transport errors are returned.
+func (c *Client) Health(opts ...CallOption) (bool, error) {
+	return c.nodeHealth(c.masterNode, opts...)
+}
+
+// nodeHealth checks the health of the node like Health.
+func (c *Client) nodeHealth(node *Node, opts ...CallOption) (bool, error) {
 	method := http.MethodGet
-	url := fmt.Sprintf("%s://%s:%s/health", c.masterNode.Protocol, c.masterNode.Host, c.masterNode.Port)
-	resp, err := c.apiCall(method, url, nil)
-	if err != nil {
-		return false
+	url := fmt.Sprintf("%s://%s:%s/health", node.Protocol, node.Host, node.Port)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if errors.Is(err, ErrServiceUnavailable) || errors.Is(err, ErrServerError) {
+		return false, nil
+	} else if err != nil {
+		return false, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusServiceUnavailable {
-		return false
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return false, nil
 	}
 	type healthResponse struct {
 		OK bool `json:"ok"`
 	}
 	var health healthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
-		return false
+		return false, nil
+	}
+	return health.OK, nil
+}
+
+// MemoryStats is the memory usage of the Typesense node.
+type MemoryStats struct {
+	SystemMemoryUsedBytes      int64
+	SystemMemoryTotalBytes     int64
+	TypesenseMemoryActiveBytes int64
+}
+
+// Metrics retrieves the current metrics of the Typesense node, e.g.
+// CPU, memory and disk usage. Typesense reports the values as strings.
+func (c *Client) Metrics(opts ...CallOption) (map[string]string, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf("%s://%s:%s/metrics.json", c.masterNode.Protocol, c.masterNode.Host, c.masterNode.Port)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var metrics map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(metrics))
+	for name, value := range metrics {
+		values[name] = fmt.Sprint(value)
+	}
+	return values, nil
+}
+
+// MemoryUsage retrieves the memory usage of the Typesense node from its
+// metrics. Metrics missing from the response are reported as zero.
+func (c *Client) MemoryUsage(opts ...CallOption) (*MemoryStats, error) {
+	metrics, err := c.Metrics(opts...)
+	if err != nil {
+		return nil, err
+	}
+	metricValue := func(name string) int64 {
+		value, _ := strconv.ParseInt(metrics[name], 10, 64)
+		return value
+	}
+	return &MemoryStats{
+		SystemMemoryUsedBytes:      metricValue("system_memory_used_bytes"),
+		SystemMemoryTotalBytes:     metricValue("system_memory_total_bytes"),
+		TypesenseMemoryActiveBytes: metricValue("typesense_memory_active_bytes"),
+	}, nil
+}
+
+// PreflightReport is the result of a preflight check of the client
+// configuration against the Typesense API.
+type PreflightReport struct {
+	// Healthy whether the Typesense node is healthy.
+	Healthy bool
+
+	// Authorized whether the API key is accepted by Typesense.
+	Authorized bool
+
+	// AdminKey whether the API key is allowed to manage API keys,
+	// which is usually only granted to admin keys.
+	AdminKey bool
+
+	// Version is the Typesense server version.
+	Version string
+
+	// NumCollections is the number of collections the API key can list.
+	NumCollections int
+}
+
+// Preflight validates the client configuration in a single call. It
+// checks the node health, verifies the API key by listing collections,
+// retrieves the server version and checks whether the API key is an
+// admin key. The report is returned with the checks made so far even
+// when one of them fails.
+func (c *Client) Preflight(ctx context.Context) (*PreflightReport, error) {
+	withContext := WithContext(ctx)
+	report := PreflightReport{}
+	healthy, err := c.Health(withContext)
+	if err != nil {
+		return &report, err
+	}
+	if report.Healthy = healthy; !report.Healthy {
+		return &report, ErrConnNotReady
+	}
+	collections, err := c.RetrieveCollections(withContext)
+	if err != nil {
+		return &report, err
 	}
-	return health.OK
+	report.Authorized = true
+	report.NumCollections = len(collections)
+	if report.Version, err = c.DebugInfo(withContext); err != nil {
+		return &report, err
+	}
+	method := http.MethodGet
+	url := fmt.Sprintf("%s://%s:%s/%s", c.masterNode.Protocol, c.masterNode.Host, c.masterNode.Port, keysEndpoint)
+	resp, err := c.apiCall(method, url, nil, withContext)
+	if err != nil {
+		return &report, err
+	}
+	defer resp.Body.Close()
+	report.AdminKey = resp.StatusCode == http.StatusOK
+	return &report, nil
 }
 
-func (c *Client) apiCall(method, url string, body []byte) (*http.Response, error) {
-	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
-	req.Header.Add(defaultHeaderKey, c.masterNode.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	return c.httpClient.Do(req)
+func (c *Client) apiCall(method, url string, body []byte, opts ...CallOption) (*http.Response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	options := c.newCallOptions(opts)
+	if options.err != nil {
+		return nil, options.err
+	}
+	ctx, cancel := options.context()
+	maxAttempts := c.maxAttempts(method)
+	if options.bodyReader != nil {
+		maxAttempts = 1
+	}
+	apiKey := c.masterNode.APIKey
+	if options.apiKey != "" {
+		apiKey = options.apiKey
+	}
+	var resp *http.Response
+	var err error
+	for attempt := 1; ; attempt++ {
+		compress := c.requestCompression && options.compressibleBody && options.bodyReader == nil && len(body) >= c.compressionThreshold
+		var reqBody io.Reader = bytes.NewReader(body)
+		if compress {
+			reqBody = gzipReader(body)
+		} else if options.bodyReader != nil {
+			reqBody = options.bodyReader
+		}
+		req, _ := http.NewRequestWithContext(ctx, method, options.withQuery(url), reqBody)
+		req.Header.Add("Content-Type", "application/json")
+		if compress {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		setHeaders(req.Header, c.defaultHeaders)
+		setHeaders(req.Header, options.headers)
+		req.Header.Set(defaultHeaderKey, apiKey)
+		resp, err = c.doRequest(req, attempt)
+		if attempt >= maxAttempts || !shouldRetry(ctx, resp, err) {
+			break
+		}
+		delay := c.retryDelay(attempt, resp)
+		c.countRetry(resp)
+		c.log().Debugf("typesense: retrying %s %s in %v", method, url, delay)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if !sleepContext(ctx, delay) {
+			resp, err = nil, ctx.Err()
+			break
+		}
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		cancel()
+		return nil, tooManyRequestsError(resp)
+	}
+	if resp.StatusCode == http.StatusInternalServerError || resp.StatusCode == http.StatusServiceUnavailable {
+		apiErr := newAPIError(resp)
+		resp.Body.Close()
+		cancel()
+		return nil, apiErr
+	}
+	resp.Body = cancelReadCloser{resp.Body, cancel}
+	if c.maxResponseBytes > 0 && !options.unlimitedResponse {
+		resp.Body = &limitedReadCloser{resp.Body, c.maxResponseBytes}
+	}
+	return resp, nil
+}
+
+// gzipReader streams the gzip compression of the body, compressing it
+// while it is read instead of buffering the compressed bytes.
+func gzipReader(body []byte) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		gzipWriter := gzip.NewWriter(writer)
+		_, err := gzipWriter.Write(body)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
+
+// setHeaders sets the headers of the request, replacing the values of
+// the headers it already has.
+func setHeaders(header, headers http.Header) {
+	for name, values := range headers {
+		header[name] = values
+	}
+}
+
+// newAPIError decodes the error response into an *APIError, falling
+// back to the status text when the response has no message.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = strings.ToLower(http.StatusText(resp.StatusCode))
+	}
+	return apiErr
+}
+
+// resourceNotFoundError tells apart a missing collection from a missing
+// resource of the collection, e.g. a synonym, Typesense answers both
+// with a not found status and only the message differs.
+func resourceNotFoundError(body io.Reader, notFound error) error {
+	var apiResponse APIResponse
+	if err := json.NewDecoder(body).Decode(&apiResponse); err == nil &&
+		strings.Contains(strings.ToLower(apiResponse.Message), "collection") {
+		return ErrCollectionNotFound
+	}
+	return notFound
 }
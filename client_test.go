@@ -1,11 +1,20 @@
 package typesense
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/GianOrtiz/typesense-go/mock"
 )
@@ -22,10 +31,31 @@ var (
 )
 
 func TestNewClient(t *testing.T) {
-	timeout := 2
-	client := NewClient(testMasterNode, timeout)
+	client := NewClient(testMasterNode)
 	if client == nil {
-		t.Errorf("Expected to receive a configured client, received <nil>")
+		t.Fatalf("Expected to receive a configured client, received <nil>")
+	}
+	if client.timeout != defaultTimeout {
+		t.Errorf("Expected to receive the default timeout %v, received %v", defaultTimeout, client.timeout)
+	}
+	if _, ok := client.httpClient.(*http.Client); !ok {
+		t.Errorf("Expected to receive the default HTTP client, received %T", client.httpClient)
+	}
+}
+
+func TestNewClient_options(t *testing.T) {
+	httpClient := &http.Client{}
+	client := NewClient(testMasterNode, WithTimeout(2*time.Second), WithHTTPClient(httpClient))
+	if client.timeout != 2*time.Second {
+		t.Errorf("Expected to receive timeout %v, received %v", 2*time.Second, client.timeout)
+	}
+	if client.httpClient != httpClient {
+		t.Errorf("Expected to receive the given HTTP client, received %v", client.httpClient)
+	}
+	transport := &http.Transport{}
+	client = NewClient(testMasterNode, WithTransport(transport))
+	if httpClient, ok := client.httpClient.(*http.Client); !ok || httpClient.Transport != transport {
+		t.Errorf("Expected to receive an HTTP client with the given transport, received %v", client.httpClient)
 	}
 }
 
@@ -61,6 +91,55 @@ func TestPing_notReady(t *testing.T) {
 	}
 }
 
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{http.StatusOK, `{"ok": true}`, true},
+		{http.StatusOK, `{"ok": false}`, false},
+		{http.StatusServiceUnavailable, `{"ok": false}`, false},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: test.statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(test.body)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		healthy, err := client.Health()
+		if err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		if healthy != test.expected {
+			t.Errorf("Expected health %v for status %d and body %s, received %v", test.expected, test.statusCode, test.body, healthy)
+		}
+	}
+}
+
+func TestHealth_transportError(t *testing.T) {
+	transportErr := fmt.Errorf("connection refused")
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, transportErr
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	healthy, err := client.Health()
+	if err != transportErr {
+		t.Errorf("Expected to receive error %v, received %v", transportErr, err)
+	}
+	if healthy {
+		t.Errorf("Expected to be unhealthy on transport errors")
+	}
+}
+
 func TestDebugInfo(t *testing.T) {
 	defaultVersion := "0.11"
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
@@ -81,3 +160,408 @@ func TestDebugInfo(t *testing.T) {
 		t.Errorf("Expected to receive value %v, received %v", defaultVersion, version)
 	}
 }
+
+func TestDebug(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet || req.URL.Path != "/debug" {
+			t.Errorf("Expected to GET /debug, requested %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"state": 1, "version": "0.25.2"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	debug, err := client.Debug()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if expected := (DebugResponse{Version: "0.25.2", State: 1}); *debug != expected {
+		t.Errorf("Expected to receive %+v, received %+v", expected, *debug)
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+		}, nil
+	}
+	client.httpClient = mockClient
+	if _, err := client.Debug(); err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		var body string
+		switch req.URL.Path {
+		case "/health":
+			body = `{"ok": true}`
+		case "/collections":
+			body = `[{"name": "companies", "num_documents": 0, "fields": []}, {"name": "books", "num_documents": 0, "fields": []}]`
+		case "/debug":
+			body = `{"state": 1, "version": "0.25.1"}`
+		case "/keys":
+			body = `{"keys": []}`
+		default:
+			t.Errorf("Unexpected request to %v", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	report, err := client.Preflight(context.Background())
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := PreflightReport{
+		Healthy:        true,
+		Authorized:     true,
+		AdminKey:       true,
+		Version:        "0.25.1",
+		NumCollections: 2,
+	}
+	if *report != expected {
+		t.Errorf("Expected to receive %v, received %v", expected, *report)
+	}
+}
+
+func TestPreflight_unauthorized(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/health" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"ok": true}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	report, err := client.Preflight(context.Background())
+	if err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+	if !report.Healthy || report.Authorized {
+		t.Errorf("Expected to receive a healthy but unauthorized report, received %v", *report)
+	}
+}
+
+func TestMemoryUsage(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/metrics.json" {
+			t.Errorf("Expected to request /metrics.json, requested %v", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"system_cpu_active_percentage": "0.00",
+				"system_memory_total_bytes": "6218739712",
+				"system_memory_used_bytes": "2407231488",
+				"typesense_memory_active_bytes": "29216768"
+			}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	stats, err := client.MemoryUsage()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := MemoryStats{
+		SystemMemoryUsedBytes:      2407231488,
+		SystemMemoryTotalBytes:     6218739712,
+		TypesenseMemoryActiveBytes: 29216768,
+	}
+	if *stats != expected {
+		t.Errorf("Expected to receive %v, received %v", expected, *stats)
+	}
+}
+
+func TestMemoryUsage_missingMetrics(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"system_memory_used_bytes": "1024"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	stats, err := client.MemoryUsage()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := (MemoryStats{SystemMemoryUsedBytes: 1024}); *stats != expected {
+		t.Errorf("Expected to receive %v, received %v", expected, *stats)
+	}
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		maxResponseBytes: 16,
+	}
+	_, err := client.Search("books", SearchParameters{Q: "harry potter", QueryBy: []string{"title"}})
+	if err != ErrResponseTooLarge {
+		t.Errorf("Expected to receive error %v, received %v", ErrResponseTooLarge, err)
+	}
+	client.maxResponseBytes = int64(len(searchResultTest))
+	if _, err := client.Search("books", SearchParameters{Q: "harry potter", QueryBy: []string{"title"}}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestWithMaxResponseBytes_exportNotLimited(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "title": "Harry Potter"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		maxResponseBytes: 16,
+	}
+	body, err := client.ExportDocuments(collectionNameTest)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	defer body.Close()
+	if _, err := ioutil.ReadAll(body); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	node := &Node{
+		Host:     serverURL.Hostname(),
+		Port:     serverURL.Port(),
+		Protocol: "https",
+		APIKey:   "secret",
+	}
+	if ok, err := NewClient(node).Health(); err == nil || ok {
+		t.Errorf("Expected the self-signed certificate to be rejected, received %v", err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	client := NewClient(node, WithTLSConfig(&tls.Config{RootCAs: rootCAs}))
+	if ok, err := client.Health(); err != nil || !ok {
+		t.Errorf("Expected to trust the certificate of the root CAs, received %v", err)
+	}
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	client = NewClient(node, WithHTTPClient(httpClient), WithInsecureSkipVerify())
+	if ok, err := client.Health(); err != nil || !ok {
+		t.Errorf("Expected to skip the verification of the certificate, received %v", err)
+	}
+	if tlsConfig := httpClient.Transport.(*http.Transport).TLSClientConfig; tlsConfig != nil && tlsConfig.InsecureSkipVerify {
+		t.Errorf("Expected the given HTTP client to not be changed")
+	}
+	client = NewClient(node, WithTLSConfig(&tls.Config{RootCAs: rootCAs}), WithTLSConfig(nil))
+	if ok, err := client.Health(); err != nil || !ok {
+		t.Errorf("Expected a nil TLS configuration to be ignored, received %v", err)
+	}
+	transport := roundTripperFunc(http.DefaultTransport.RoundTrip)
+	client = NewClient(node, WithTransport(transport), WithTLSConfig(&tls.Config{RootCAs: rootCAs}))
+	if _, err := client.Health(); !errors.Is(err, ErrUnsupportedTransport) {
+		t.Errorf("Expected to receive ErrUnsupportedTransport for a custom transport, received %v", err)
+	}
+	client = NewClient(node, WithInsecureSkipVerify())
+	client.httpClient = mockClient
+	client.updateTLSConfig(func(*tls.Config) {})
+	if _, err := client.Health(); !errors.Is(err, ErrUnsupportedTransport) {
+		t.Errorf("Expected to receive ErrUnsupportedTransport for a custom HTTP client, received %v", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClientWithHealthCheck(t *testing.T) {
+	var checked []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		checked = append(checked, req.URL.Host)
+		if req.URL.Host == "localhost:8108" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"ok": true}`)),
+		}, nil
+	}
+	replica := &Node{Host: "replica", Port: "8108", Protocol: "http", APIKey: "secret"}
+	client, err := NewClientWithHealthCheck(testMasterNode, WithReadReplicas(replica), func(c *Client) {
+		c.httpClient = mockClient
+	})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if client.masterNode != replica {
+		t.Errorf("Expected to pick the healthy replica, received %+v", client.masterNode)
+	}
+	if expected := []string{"localhost:8108", "replica:8108"}; !reflect.DeepEqual(checked, expected) {
+		t.Errorf("Expected to check the nodes %v, checked %v", expected, checked)
+	}
+	_, err = NewClientWithHealthCheck(testMasterNode, func(c *Client) {
+		c.httpClient = mockClient
+	})
+	if !errors.Is(err, ErrConnNotReady) || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected to receive error %v with the failure of the node, received %v", ErrConnNotReady, err)
+	}
+}
+
+func TestLastUsedNode(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"ok": true}`)),
+		}, nil
+	}
+	replica := &Node{Host: "replica", Port: "8108", Protocol: "http", APIKey: "secret"}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		readReplicaNodes: []*Node{replica},
+	}
+	if node := client.LastUsedNode(); node != nil {
+		t.Errorf("Expected no node before the first request, received %+v", node)
+	}
+	if _, err := client.nodeHealth(replica); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if node := client.LastUsedNode(); node != replica {
+		t.Errorf("Expected the replica to serve the last request, received %+v", node)
+	}
+	if _, err := client.Health(); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if node := client.LastUsedNode(); node != testMasterNode {
+		t.Errorf("Expected the master node to serve the last request, received %+v", node)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	calls := map[string]func(c *Client) error{
+		"RetrieveAlias": func(c *Client) error {
+			_, err := c.RetrieveAlias("companies")
+			return err
+		},
+		"RetrieveAliases": func(c *Client) error {
+			_, err := c.RetrieveAliases()
+			return err
+		},
+		"DeleteAlias": func(c *Client) error {
+			_, err := c.DeleteAlias("companies")
+			return err
+		},
+		"RetrieveSynonym": func(c *Client) error {
+			_, err := c.RetrieveSynonym("companies", "coat-synonyms")
+			return err
+		},
+		"RetrieveSynonyms": func(c *Client) error {
+			_, err := c.RetrieveSynonyms("companies")
+			return err
+		},
+		"DeleteSynonym": func(c *Client) error {
+			_, err := c.DeleteSynonym("companies", "coat-synonyms")
+			return err
+		},
+		"RetrieveOverride": func(c *Client) error {
+			_, err := c.RetrieveOverride("companies", "customize-apple")
+			return err
+		},
+		"RetrieveOverrides": func(c *Client) error {
+			_, err := c.RetrieveOverrides("companies")
+			return err
+		},
+		"DeleteOverride": func(c *Client) error {
+			return c.DeleteOverride("companies", "customize-apple")
+		},
+		"RetrievePreset": func(c *Client) error {
+			_, err := c.RetrievePreset("listing")
+			return err
+		},
+		"RetrievePresets": func(c *Client) error {
+			_, err := c.RetrievePresets()
+			return err
+		},
+		"DeletePreset": func(c *Client) error {
+			return c.DeletePreset("listing")
+		},
+		"RetrieveAPIKeys": func(c *Client) error {
+			_, err := c.RetrieveAPIKeys()
+			return err
+		},
+		"RetrieveCollection": func(c *Client) error {
+			_, err := c.RetrieveCollection("companies")
+			return err
+		},
+		"RetrieveCollections": func(c *Client) error {
+			_, err := c.RetrieveCollections()
+			return err
+		},
+		"DeleteCollection": func(c *Client) error {
+			_, err := c.DeleteCollection("companies")
+			return err
+		},
+		"RetrieveDocument": func(c *Client) error {
+			return c.RetrieveDocument("companies", "124").Error
+		},
+		"Metrics": func(c *Client) error {
+			_, err := c.Metrics()
+			return err
+		},
+	}
+	for _, statusCode := range []int{http.StatusForbidden, http.StatusBadGateway} {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		for name, call := range calls {
+			var apiErr *APIError
+			if err := call(&client); !errors.As(err, &apiErr) || apiErr.StatusCode != statusCode {
+				t.Errorf("Expected %s to receive an *APIError with status %d, received %v", name, statusCode, err)
+			}
+		}
+	}
+}
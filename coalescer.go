@@ -0,0 +1,148 @@
+package typesense
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCoalescerFlushInterval is the flush interval of the coalescers
+// created with a zero or negative flush interval.
+const defaultCoalescerFlushInterval = time.Second
+
+// UpdateCoalescer merges rapid partial updates of the same documents
+// and writes them as a single batched import at every flush interval.
+// It is safe for concurrent use. Close must be called to flush the
+// pending updates and stop the background flushes.
+type UpdateCoalescer struct {
+	client         *Client
+	collectionName string
+
+	mu      sync.Mutex
+	pending map[string]map[string]interface{}
+	order   []string
+	err     error
+	closed  bool
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewUpdateCoalescer creates a coalescer of partial updates of the
+// collection documents, flushing them every flushInterval. A zero or
+// negative flushInterval flushes them every second.
+func (c *Client) NewUpdateCoalescer(collectionName string, flushInterval time.Duration) *UpdateCoalescer {
+	if flushInterval <= 0 {
+		flushInterval = defaultCoalescerFlushInterval
+	}
+	coalescer := UpdateCoalescer{
+		client:         c,
+		collectionName: collectionName,
+		pending:        make(map[string]map[string]interface{}),
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+	go coalescer.run(flushInterval)
+	return &coalescer
+}
+
+func (u *UpdateCoalescer) run(flushInterval time.Duration) {
+	defer close(u.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := u.Flush(); err != nil {
+				u.mu.Lock()
+				if u.err == nil {
+					u.err = err
+				}
+				u.mu.Unlock()
+			}
+		case <-u.done:
+			return
+		}
+	}
+}
+
+// Update adds a partial update of the document to the pending updates,
+// merging it with the pending update of the same document if any. The
+// fields of later updates win. It returns ErrCoalescerClosed once the
+// coalescer is closed, the update is not written.
+func (u *UpdateCoalescer) Update(documentID string, partial map[string]interface{}) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.closed {
+		return ErrCoalescerClosed
+	}
+	fields, ok := u.pending[documentID]
+	if !ok {
+		fields = make(map[string]interface{}, len(partial)+1)
+		u.pending[documentID] = fields
+		u.order = append(u.order, documentID)
+	}
+	for name, value := range partial {
+		fields[name] = value
+	}
+	fields["id"] = documentID
+	return nil
+}
+
+// Flush writes the pending updates immediately.
+func (u *UpdateCoalescer) Flush() error {
+	u.mu.Lock()
+	pending, order := u.pending, u.order
+	u.pending = make(map[string]map[string]interface{})
+	u.order = nil
+	u.mu.Unlock()
+	if len(order) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, len(order))
+	for i, documentID := range order {
+		documents[i] = pending[documentID]
+	}
+	body, err := encodeJSONL(u.client, documents)
+	if err != nil {
+		return err
+	}
+	results, err := u.client.importJSONL(u.collectionName, "update", body)
+	if err != nil {
+		return err
+	}
+	var failures []string
+	for i, result := range results {
+		if !result.Success && i < len(order) {
+			failures = append(failures, fmt.Sprintf("%s: %s", order[i], result.Error))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("couldn't update documents: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Close stops the background flushes and flushes the pending updates,
+// the updates made after it fail with ErrCoalescerClosed. It returns
+// the first error of the background flushes, if any, or the error of
+// the last flush.
+func (u *UpdateCoalescer) Close() error {
+	var err error
+	u.closeOnce.Do(func() {
+		close(u.done)
+		<-u.stopped
+		u.mu.Lock()
+		u.closed = true
+		u.mu.Unlock()
+		err = u.Flush()
+		u.mu.Lock()
+		if u.err != nil {
+			err = u.err
+		}
+		u.mu.Unlock()
+	})
+	return err
+}
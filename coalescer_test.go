@@ -0,0 +1,67 @@
+package typesense
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateCoalescer(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if action := req.URL.Query().Get("action"); action != "update" {
+			t.Errorf("Expected to import with action %v, imported with %v", "update", action)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	coalescer := client.NewUpdateCoalescer(collectionNameTest, time.Hour)
+	coalescer.Update("1", map[string]interface{}{"views": 1})
+	coalescer.Update("1", map[string]interface{}{"views": 2, "likes": 1})
+	if err := coalescer.Close(); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := `{"id":"1","likes":1,"views":2}` + "\n"
+	if len(bodies) != 1 || bodies[0] != expected {
+		t.Errorf("Expected a single write %q, received %q", expected, bodies)
+	}
+	if err := coalescer.Update("1", map[string]interface{}{"views": 3}); err != ErrCoalescerClosed {
+		t.Errorf("Expected to receive error %v, received %v", ErrCoalescerClosed, err)
+	}
+}
+
+func TestUpdateCoalescer_defaultFlushInterval(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	for _, flushInterval := range []time.Duration{0, -time.Second} {
+		coalescer := client.NewUpdateCoalescer(collectionNameTest, flushInterval)
+		if err := coalescer.Update("1", map[string]interface{}{"views": 1}); err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		if err := coalescer.Close(); err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+	}
+}
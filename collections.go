@@ -1,10 +1,18 @@
 package typesense
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
 )
 
 const collectionsEndpoint = "collections"
@@ -15,6 +23,18 @@ type CollectionSchema struct {
 	Name                string            `json:"name"`
 	Fields              []CollectionField `json:"fields"`
 	DefaultSortingField string            `json:"default_sorting_field"`
+
+	// EnableNestedFields whether object and object[] fields are
+	// indexed, it is required to declare fields of these types.
+	EnableNestedFields bool `json:"enable_nested_fields,omitempty"`
+
+	// SymbolsToIndex special characters indexed as part of the text,
+	// e.g. + to tell apart c and c++. By default they are removed.
+	SymbolsToIndex []string `json:"symbols_to_index,omitempty"`
+
+	// TokenSeparators special characters the text is split on besides
+	// spaces, e.g. - and _ so foo_bar is indexed as foo and bar.
+	TokenSeparators []string `json:"token_separators,omitempty"`
 }
 
 // Collection is the model of a collection created in the
@@ -30,15 +50,191 @@ type CollectionField struct {
 	Name  string `json:"name"`
 	Type  string `json:"type"`
 	Facet bool   `json:"facet"`
+
+	// Optional whether documents may omit the field.
+	Optional bool `json:"optional,omitempty"`
+
+	// Index whether the field is indexed, set it to false to store a
+	// field in the documents without indexing it.
+	Index *bool `json:"index,omitempty"`
+
+	// Sort whether the field can be used to sort the results, numeric
+	// fields are sortable by default.
+	Sort bool `json:"sort,omitempty"`
+
+	// Infix whether the field supports infix search.
+	Infix bool `json:"infix,omitempty"`
+
+	// Locale is the locale of the text of the field, e.g. "ja" for
+	// Japanese.
+	Locale string `json:"locale,omitempty"`
+
+	// NumDim is the number of dimensions of a float[] vector field.
+	NumDim int `json:"num_dim,omitempty"`
+
+	// Stem whether the values of the field are stemmed before they
+	// are indexed, e.g. so "running" matches "run". It is only
+	// supported by string and string[] fields.
+	Stem *bool `json:"stem,omitempty"`
+
+	// Embed makes the float[] field an auto-embedding field, Typesense
+	// generates its embedding from the values of the source fields
+	// with the model, so the documents don't need to provide it.
+	Embed *EmbedConfig `json:"embed,omitempty"`
 }
 
-// CreateCollection creates a new collection using the
-// given collection schema.
-func (c *Client) CreateCollection(collectionSchema CollectionSchema) (*Collection, error) {
+// EmbedConfig is the configuration of an auto-embedding field.
+type EmbedConfig struct {
+	// From are the names of the fields the embedding is generated from.
+	From        []string    `json:"from"`
+	ModelConfig ModelConfig `json:"model_config"`
+}
+
+// ModelConfig is the model generating the embeddings of an
+// auto-embedding field, either a built-in model, e.g.
+// ts/all-MiniLM-L12-v2, or a remote one, e.g. openai/text-embedding-3-small
+// with its API key.
+type ModelConfig struct {
+	ModelName string `json:"model_name"`
+	APIKey    string `json:"api_key,omitempty"`
+
+	// URL is the endpoint of a self-hosted model served with an
+	// OpenAI compatible API.
+	URL string `json:"url,omitempty"`
+}
+
+// fieldTypes are the types of the fields supported by Typesense.
+var fieldTypes = map[string]bool{
+	"string":     true,
+	"string[]":   true,
+	"int32":      true,
+	"int32[]":    true,
+	"int64":      true,
+	"int64[]":    true,
+	"float":      true,
+	"float[]":    true,
+	"bool":       true,
+	"bool[]":     true,
+	"geopoint":   true,
+	"geopoint[]": true,
+	"geopolygon": true,
+	"object":     true,
+	"object[]":   true,
+	"string*":    true,
+	"image":      true,
+	"auto":       true,
+}
+
+// sortingFieldTypes are the types of the fields that can be the
+// default sorting field of a collection.
+var sortingFieldTypes = map[string]bool{
+	"int32": true,
+	"int64": true,
+	"float": true,
+}
+
+// validate checks that the type of the field is supported and that
+// the options of the field are supported by its type.
+func (f CollectionField) validate() error {
+	if !fieldTypes[f.Type] {
+		return fmt.Errorf("field %s: %w: %q", f.Name, ErrInvalidFieldType, f.Type)
+	}
+	if f.Stem != nil && *f.Stem && f.Type != "string" && f.Type != "string[]" {
+		return fmt.Errorf("field %s: %w", f.Name, ErrStemNotSupported)
+	}
+	if f.Embed != nil && (f.Type != "float[]" || len(f.Embed.From) == 0 || f.Embed.ModelConfig.ModelName == "") {
+		return fmt.Errorf("field %s: %w", f.Name, ErrInvalidEmbedConfig)
+	}
+	return nil
+}
+
+// Validate checks the collection schema like ValidateCollectionSchema.
+func (s CollectionSchema) Validate() error {
+	return ValidateCollectionSchema(s)
+}
+
+// ValidateCollectionSchema checks the collection schema before it is
+// sent to Typesense. The schema must have a name and fields, the
+// field names must be unique, the type of each field must be supported
+// and its options supported by its type. Object fields require
+// EnableNestedFields. The default sorting field, if any, must be a
+// required numeric field of the schema.
+func ValidateCollectionSchema(collectionSchema CollectionSchema) error {
 	if collectionSchema.Name == "" {
-		return nil, ErrCollectionNameRequired
+		return ErrCollectionNameRequired
+	} else if err := ValidateCollectionName(collectionSchema.Name); err != nil {
+		return err
 	} else if len(collectionSchema.Fields) == 0 {
-		return nil, ErrCollectionFieldsRequired
+		return ErrCollectionFieldsRequired
+	}
+	seen := make(map[string]bool, len(collectionSchema.Fields))
+	var duplicates []string
+	for _, field := range collectionSchema.Fields {
+		if err := field.validate(); err != nil {
+			return err
+		}
+		if (field.Type == "object" || field.Type == "object[]") && !collectionSchema.EnableNestedFields {
+			return fmt.Errorf("field %s: %w", field.Name, ErrNestedFieldsDisabled)
+		}
+		if seen[field.Name] {
+			duplicates = append(duplicates, field.Name)
+		}
+		seen[field.Name] = true
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateFieldName, strings.Join(duplicates, ", "))
+	}
+	return validateDefaultSortingField(collectionSchema)
+}
+
+// validateDefaultSortingField checks that the default sorting field of
+// the schema, if any, is a required int32, int64 or float field of the
+// schema, Typesense rejects the schema otherwise.
+func validateDefaultSortingField(collectionSchema CollectionSchema) error {
+	name := collectionSchema.DefaultSortingField
+	if name == "" {
+		return nil
+	}
+	for _, field := range collectionSchema.Fields {
+		if field.Name != name {
+			continue
+		}
+		if !sortingFieldTypes[field.Type] {
+			return fmt.Errorf("%w: %s has the type %s, it must be int32, int64 or float", ErrInvalidDefaultSortingField, name, field.Type)
+		} else if field.Optional {
+			return fmt.Errorf("%w: %s is optional, every document must have it", ErrInvalidDefaultSortingField, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not a field of the schema", ErrInvalidDefaultSortingField, name)
+}
+
+// invalidCollectionNameChars are the characters not allowed in a
+// collection name, they would change the path of the requests to the
+// collection.
+const invalidCollectionNameChars = "/?#%\\"
+
+// ValidateCollectionName checks that the collection name is not empty
+// and has no characters that are not allowed, returning
+// ErrInvalidCollectionName otherwise.
+func ValidateCollectionName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidCollectionName
+	}
+	for _, char := range name {
+		if strings.ContainsRune(invalidCollectionNameChars, char) || unicode.IsControl(char) {
+			return fmt.Errorf("%w: %q has the character %q", ErrInvalidCollectionName, name, char)
+		}
+	}
+	return nil
+}
+
+// CreateCollection creates a new collection using the
+// given collection schema. The schema is checked with
+// ValidateCollectionSchema before it is sent.
+func (c *Client) CreateCollection(collectionSchema CollectionSchema, opts ...CallOption) (*Collection, error) {
+	if err := ValidateCollectionSchema(collectionSchema); err != nil {
+		return nil, err
 	}
 	method := http.MethodPost
 	url := fmt.Sprintf(
@@ -49,7 +245,7 @@ func (c *Client) CreateCollection(collectionSchema CollectionSchema) (*Collectio
 		collectionsEndpoint,
 	)
 	collectionJSON, _ := json.Marshal(collectionSchema)
-	resp, err := c.apiCall(method, url, collectionJSON)
+	resp, err := c.apiCall(method, url, collectionJSON, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,11 +255,9 @@ func (c *Client) CreateCollection(collectionSchema CollectionSchema) (*Collectio
 	} else if resp.StatusCode == http.StatusUnauthorized {
 		return nil, ErrUnauthorized
 	} else if resp.StatusCode == http.StatusBadRequest {
-		var apiResponse APIResponse
-		if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-			return nil, err
-		}
-		return nil, errors.New(apiResponse.Message)
+		return nil, schemaValidationError(newAPIError(resp))
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
 	}
 	var collectionResponse Collection
 	if err := json.NewDecoder(resp.Body).Decode(&collectionResponse); err != nil {
@@ -72,8 +266,28 @@ func (c *Client) CreateCollection(collectionSchema CollectionSchema) (*Collectio
 	return &collectionResponse, nil
 }
 
+// quotedFieldPattern matches the field names quoted in backticks in the
+// schema validation messages of Typesense, e.g. "Field `title` has an
+// invalid data type `strng`".
+var quotedFieldPattern = regexp.MustCompile("(?i)field(?: named)? `([^`]+)`")
+
+// schemaValidationError classifies the error of a rejected collection
+// schema into a *SchemaValidationError when its message refers to
+// fields of the schema, otherwise it returns the error as is.
+func schemaValidationError(apiErr *APIError) error {
+	matches := quotedFieldPattern.FindAllStringSubmatch(apiErr.Message, -1)
+	if len(matches) == 0 {
+		return apiErr
+	}
+	fields := make([]ValidationError, len(matches))
+	for i, match := range matches {
+		fields[i] = ValidationError{Field: match[1], Reason: apiErr.Message}
+	}
+	return &SchemaValidationError{Fields: fields, err: apiErr}
+}
+
 // RetrieveCollections get all collections from Typesense.
-func (c *Client) RetrieveCollections() ([]*Collection, error) {
+func (c *Client) RetrieveCollections(opts ...CallOption) ([]*Collection, error) {
 	method := http.MethodGet
 	url := fmt.Sprintf(
 		"%s://%s:%s/%s",
@@ -82,13 +296,15 @@ func (c *Client) RetrieveCollections() ([]*Collection, error) {
 		c.masterNode.Port,
 		collectionsEndpoint,
 	)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusUnauthorized {
 		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
 	}
 	var collections []*Collection
 	if err := json.NewDecoder(resp.Body).Decode(&collections); err != nil {
@@ -97,9 +313,91 @@ func (c *Client) RetrieveCollections() ([]*Collection, error) {
 	return collections, nil
 }
 
+// RetrieveCollectionResolvingAlias retrieves the collection like
+// RetrieveCollection, resolving the name first as an alias. It returns
+// the name of the collection the alias points to, or the name itself
+// when it is not an alias, e.g. to log the collection an alias points
+// to during a swap.
+func (c *Client) RetrieveCollectionResolvingAlias(name string, opts ...CallOption) (*Collection, string, error) {
+	resolvedName := name
+	alias, err := c.RetrieveAlias(name, opts...)
+	if err == nil {
+		resolvedName = alias.CollectionName
+	} else if err != ErrAliasNotFound {
+		return nil, "", err
+	}
+	collection, err := c.RetrieveCollection(resolvedName, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return collection, resolvedName, nil
+}
+
+// StreamCollections retrieves all collections from Typesense like
+// RetrieveCollections, decoding them one by one as the response is
+// read, so the memory stays bounded on clusters with thousands of
+// collections. The collections are sent to the first channel, which is
+// closed once the response is read. The second channel receives the
+// transport or decode error, if any, and is closed after the first
+// one. The caller must drain the collections or cancel the context of
+// the call, see WithContext.
+func (c *Client) StreamCollections(opts ...CallOption) (<-chan *Collection, <-chan error) {
+	collections := make(chan *Collection)
+	errs := make(chan error, 1)
+	ctx := c.newCallOptions(opts).ctx
+	go func() {
+		defer close(errs)
+		defer close(collections)
+		if err := c.streamCollections(ctx, collections, opts...); err != nil {
+			errs <- err
+		}
+	}()
+	return collections, errs
+}
+
+func (c *Client) streamCollections(ctx context.Context, collections chan<- *Collection, opts ...CallOption) error {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, append(opts, withUnlimitedResponse())...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+	decoder := json.NewDecoder(resp.Body)
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if token != json.Delim('[') {
+		return fmt.Errorf("expected an array of collections, received %v", token)
+	}
+	for decoder.More() {
+		var collection Collection
+		if err := decoder.Decode(&collection); err != nil {
+			return err
+		}
+		select {
+		case collections <- &collection:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
 // RetrieveCollection retrieves a single collection by
 // its name.
-func (c *Client) RetrieveCollection(collectionName string) (*Collection, error) {
+func (c *Client) RetrieveCollection(collectionName string, opts ...CallOption) (*Collection, error) {
 	method := http.MethodGet
 	url := fmt.Sprintf(
 		"%s://%s:%s/%s/%s",
@@ -109,7 +407,174 @@ func (c *Client) RetrieveCollection(collectionName string) (*Collection, error)
 		collectionsEndpoint,
 		collectionName,
 	)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var collection Collection
+	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// RetrieveSchema retrieves the schema of a single collection by its
+// name, without the fields managed by Typesense like the number of
+// documents, e.g. to compare it with SchemaDiff.
+func (c *Client) RetrieveSchema(collectionName string, opts ...CallOption) (*CollectionSchema, error) {
+	collection, err := c.RetrieveCollection(collectionName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	schema := collection.Schema()
+	return &schema, nil
+}
+
+// RetrieveCollectionsByNames retrieves the named collections concurrently,
+// with at most WithMaxConcurrentRequests requests in flight. The returned
+// errors are aligned with the names, a collection that couldn't be
+// retrieved has its error set and is missing from the returned map
+// without aborting the retrieval of the others. When the context of
+// the call is done, the names not retrieved yet get its error.
+func (c *Client) RetrieveCollectionsByNames(names []string, opts ...CallOption) (map[string]*Collection, []error) {
+	collections := make(map[string]*Collection, len(names))
+	errs := make([]error, len(names))
+	ctx := c.newCallOptions(opts).ctx
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.maxConcurrentRequests())
+	for i, name := range names {
+		if ctx.Err() == nil {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(names); j++ {
+				errs[j] = err
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			collection, err := c.RetrieveCollection(name, opts...)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			mu.Lock()
+			collections[name] = collection
+			mu.Unlock()
+		}(i, name)
+	}
+	wg.Wait()
+	return collections, errs
+}
+
+// EnsureCollection creates the collection with the schema unless a
+// collection with its name already exists, in which case it returns
+// the existing collection, e.g. to set up the collections at startup.
+// When the fields of the existing collection differ from the fields of
+// the schema, compared like SchemaDiff, it also returns an error
+// wrapping ErrSchemaMismatch that lists the differences.
+func (c *Client) EnsureCollection(schema CollectionSchema, opts ...CallOption) (*Collection, error) {
+	collection, err := c.CreateCollection(schema, opts...)
+	if err != ErrCollectionDuplicate {
+		return collection, err
+	}
+	collection, err = c.RetrieveCollection(schema.Name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var differences []string
+	for _, change := range SchemaDiff(collection.CollectionSchema, schema) {
+		switch {
+		case change.Kind == FieldAdded:
+			differences = append(differences, fmt.Sprintf("missing field %s", change.Name))
+		case change.Kind == FieldDropped:
+			differences = append(differences, fmt.Sprintf("extra field %s", change.Name))
+		case change.TypeChanged():
+			differences = append(differences, fmt.Sprintf("field %s is %s instead of %s", change.Name, change.Old.Type, change.New.Type))
+		default:
+			differences = append(differences, fmt.Sprintf("field %s has other options", change.Name))
+		}
+	}
+	if len(differences) > 0 {
+		return collection, fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(differences, ", "))
+	}
+	return collection, nil
+}
+
+// CloneCollection creates an empty collection named newName with the
+// schema of the source collection, e.g. to import the documents into it
+// and swap an alias to it. It returns ErrCollectionNotFound when the
+// source doesn't exist and ErrCollectionDuplicate when the new
+// collection already exists.
+func (c *Client) CloneCollection(sourceName, newName string, opts ...CallOption) (*Collection, error) {
+	source, err := c.RetrieveCollection(sourceName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	schema := source.CollectionSchema
+	schema.Name = newName
+	return c.CreateCollection(schema, opts...)
+}
+
+// CollectionFieldUpdate is a field to add to or drop from a collection
+// schema with UpdateCollection.
+type CollectionFieldUpdate struct {
+	CollectionField
+
+	// Drop whether the field is dropped from the schema, only the name
+	// of the field is needed to drop it.
+	Drop bool `json:"drop,omitempty"`
+}
+
+// MarshalJSON encodes the field update, a dropped field is encoded
+// with its name only.
+func (f CollectionFieldUpdate) MarshalJSON() ([]byte, error) {
+	if f.Drop {
+		type droppedField struct {
+			Name string `json:"name"`
+			Drop bool   `json:"drop"`
+		}
+		return json.Marshal(droppedField{f.Name, true})
+	}
+	return json.Marshal(f.CollectionField)
+}
+
+// UpdateCollection alters the schema of the collection, adding the
+// new fields and dropping the fields marked to drop. A field is
+// changed by dropping it and adding it again in the same update.
+func (c *Client) UpdateCollection(collectionName string, fields []CollectionFieldUpdate, opts ...CallOption) (*Collection, error) {
+	method := http.MethodPatch
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+	)
+	type updateRequest struct {
+		Fields []CollectionFieldUpdate `json:"fields"`
+	}
+	updateJSON, err := json.Marshal(updateRequest{fields})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.apiCall(method, url, updateJSON, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +583,8 @@ func (c *Client) RetrieveCollection(collectionName string) (*Collection, error)
 		return nil, ErrCollectionNotFound
 	} else if resp.StatusCode == http.StatusUnauthorized {
 		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
 	}
 	var collection Collection
 	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
@@ -127,7 +594,7 @@ func (c *Client) RetrieveCollection(collectionName string) (*Collection, error)
 }
 
 // DeleteCollection deletes a collection by its name.
-func (c *Client) DeleteCollection(collectionName string) (*Collection, error) {
+func (c *Client) DeleteCollection(collectionName string, opts ...CallOption) (*Collection, error) {
 	method := http.MethodDelete
 	url := fmt.Sprintf(
 		"%s://%s:%s/%s/%s",
@@ -137,7 +604,7 @@ func (c *Client) DeleteCollection(collectionName string) (*Collection, error) {
 		collectionsEndpoint,
 		collectionName,
 	)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +613,8 @@ func (c *Client) DeleteCollection(collectionName string) (*Collection, error) {
 		return nil, ErrCollectionNotFound
 	} else if resp.StatusCode == http.StatusUnauthorized {
 		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
 	}
 	var collection Collection
 	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
@@ -153,3 +622,185 @@ func (c *Client) DeleteCollection(collectionName string) (*Collection, error) {
 	}
 	return &collection, nil
 }
+
+// SafeDeleteCollection deletes the collection only when no alias points
+// to it, otherwise it returns an error wrapping ErrCollectionAliased
+// with the names of the aliases, so live searches through an alias are
+// not broken by accident. With force it deletes the collection anyway.
+func (c *Client) SafeDeleteCollection(collectionName string, force bool, opts ...CallOption) error {
+	if !force {
+		aliases, err := c.AliasesForCollection(collectionName, opts...)
+		if err != nil {
+			return err
+		}
+		if len(aliases) > 0 {
+			names := make([]string, len(aliases))
+			for i, alias := range aliases {
+				names[i] = alias.Name
+			}
+			return fmt.Errorf("%w: %s", ErrCollectionAliased, strings.Join(names, ", "))
+		}
+	}
+	_, err := c.DeleteCollection(collectionName, opts...)
+	return err
+}
+
+// Schema returns the schema of the collection, without the fields
+// managed by Typesense like the number of documents. The returned
+// schema doesn't share memory with the collection.
+func (col *Collection) Schema() CollectionSchema {
+	return col.CollectionSchema.Clone()
+}
+
+// Clone returns a deep copy of the schema that can be safely mutated
+// without affecting the original schema.
+func (s CollectionSchema) Clone() CollectionSchema {
+	clone := s
+	if s.Fields != nil {
+		clone.Fields = make([]CollectionField, len(s.Fields))
+		for i, field := range s.Fields {
+			clone.Fields[i] = field.clone()
+		}
+	}
+	if s.SymbolsToIndex != nil {
+		clone.SymbolsToIndex = append([]string(nil), s.SymbolsToIndex...)
+	}
+	if s.TokenSeparators != nil {
+		clone.TokenSeparators = append([]string(nil), s.TokenSeparators...)
+	}
+	return clone
+}
+
+// clone returns a copy of the field that shares none of its options.
+func (f CollectionField) clone() CollectionField {
+	clone := f
+	if f.Index != nil {
+		index := *f.Index
+		clone.Index = &index
+	}
+	if f.Stem != nil {
+		stem := *f.Stem
+		clone.Stem = &stem
+	}
+	if f.Embed != nil {
+		embed := *f.Embed
+		if f.Embed.From != nil {
+			embed.From = append([]string(nil), f.Embed.From...)
+		}
+		clone.Embed = &embed
+	}
+	return clone
+}
+
+// SchemaFromSampleJSON infers a collection schema from a sample JSON
+// document, e.g. to bootstrap a schema from real data. The fields are
+// declared in the order of their names with the type of their sample
+// value, all of them optional. Integers are inferred as int64, other
+// numbers as float, null values and empty arrays as auto, and nested
+// objects as object fields, which enables the nested fields of the
+// schema. The id of the document is not declared as a field.
+func SchemaFromSampleJSON(name string, sample []byte) (CollectionSchema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(sample))
+	decoder.UseNumber()
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return CollectionSchema{}, fmt.Errorf("couldn't decode sample document: %w", err)
+	}
+	schema := CollectionSchema{Name: name}
+	fieldNames := make([]string, 0, len(document))
+	for fieldName := range document {
+		if fieldName != "id" {
+			fieldNames = append(fieldNames, fieldName)
+		}
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		fieldType := sampleFieldType(document[fieldName])
+		if fieldType == "object" || fieldType == "object[]" {
+			schema.EnableNestedFields = true
+		}
+		schema.Fields = append(schema.Fields, CollectionField{
+			Name:     fieldName,
+			Type:     fieldType,
+			Optional: true,
+		})
+	}
+	return schema, nil
+}
+
+// sampleFieldType returns the field type of a sample value decoded
+// with json.Number numbers.
+func sampleFieldType(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "int64"
+		}
+		return "float"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		elementType := ""
+		for _, element := range value {
+			if element == nil {
+				continue
+			}
+			current := sampleFieldType(element)
+			if elementType == "" || elementType == "int64" && current == "float" {
+				elementType = current
+			} else if current != elementType && !(elementType == "float" && current == "int64") {
+				return "auto"
+			}
+		}
+		if elementType == "" || strings.HasSuffix(elementType, "[]") || elementType == "auto" {
+			return "auto"
+		}
+		return elementType + "[]"
+	default:
+		return "auto"
+	}
+}
+
+// ResolvedFields retrieves the collection and returns its fields with
+// the concrete types resolved by Typesense. Fields declared with the
+// auto detection types (auto and string*) are resolved by Typesense
+// when documents are indexed, so only the resolved fields are returned.
+func (c *Client) ResolvedFields(collectionName string, opts ...CallOption) ([]CollectionField, error) {
+	collection, err := c.RetrieveCollection(collectionName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]CollectionField, 0, len(collection.Fields))
+	for _, field := range collection.Fields {
+		if field.Type == "auto" || field.Type == "string*" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Hash returns a stable hash of the normalized collection schema. The
+// order of the fields does not affect the hash, so two schemas that
+// only differ in the field ordering produce the same value, and the
+// options left unset are hashed as the defaults Typesense applies, like
+// in SchemaDiff, so a retrieved schema has the hash of the schema it
+// was created from. It can be used by migration tools to detect whether
+// a schema has changed.
+func (s CollectionSchema) Hash() string {
+	normalized := s
+	normalized.Fields = make([]CollectionField, len(s.Fields))
+	for i, field := range s.Fields {
+		normalized.Fields[i] = normalizedField(field)
+	}
+	sort.SliceStable(normalized.Fields, func(i, j int) bool {
+		return normalized.Fields[i].Name < normalized.Fields[j].Name
+	})
+	schemaJSON, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(schemaJSON)
+	return hex.EncodeToString(sum[:])
+}
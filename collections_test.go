@@ -2,7 +2,9 @@ package typesense
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"reflect"
@@ -56,6 +58,45 @@ func TestCreateCollection(t *testing.T) {
 	}
 }
 
+func TestCreateCollection_tokenization(t *testing.T) {
+	schema := CollectionSchema{
+		Name:            "phone_numbers",
+		Fields:          []CollectionField{{Name: "number", Type: "string"}},
+		SymbolsToIndex:  []string{"+"},
+		TokenSeparators: []string{"-", "_"},
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		var sent map[string]interface{}
+		body, _ := ioutil.ReadAll(req.Body)
+		json.Unmarshal(body, &sent)
+		if !reflect.DeepEqual(sent["symbols_to_index"], []interface{}{"+"}) {
+			t.Errorf("Expected to send symbols_to_index [+], sent %v", sent["symbols_to_index"])
+		}
+		if !reflect.DeepEqual(sent["token_separators"], []interface{}{"-", "_"}) {
+			t.Errorf("Expected to send token_separators [- _], sent %v", sent["token_separators"])
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	collection, err := client.CreateCollection(schema)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !reflect.DeepEqual(collection.CollectionSchema, schema) {
+		t.Errorf("Expected to receive the schema %+v, received %+v", schema, collection.CollectionSchema)
+	}
+	emptyJSON, _ := json.Marshal(testCollectionSchema)
+	if bytes.Contains(emptyJSON, []byte("symbols_to_index")) || bytes.Contains(emptyJSON, []byte("token_separators")) {
+		t.Errorf("Expected the unset tokenization settings to be omitted, received %s", emptyJSON)
+	}
+}
+
 func TestCreateCollection_nameRequired(t *testing.T) {
 	testData := CollectionSchema{Fields: []CollectionField{{Name: "field", Type: "string"}}}
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
@@ -94,6 +135,157 @@ func TestCreateCollection_fieldsRequired(t *testing.T) {
 	}
 }
 
+func TestCollectionField_stem(t *testing.T) {
+	stem := true
+	fieldJSON, err := json.Marshal(CollectionField{Name: "description", Type: "string", Stem: &stem})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expectedJSON := `{"name":"description","type":"string","facet":false,"stem":true}`
+	if string(fieldJSON) != expectedJSON {
+		t.Errorf("Expected to marshal %s, marshalled %s", expectedJSON, fieldJSON)
+	}
+	fieldJSON, _ = json.Marshal(CollectionField{Name: "description", Type: "string"})
+	if strings.Contains(string(fieldJSON), "stem") {
+		t.Errorf("Expected to omit stem when it is not set, marshalled %s", fieldJSON)
+	}
+	var field CollectionField
+	if err := json.Unmarshal([]byte(`{"name":"description","type":"string","stem":true}`), &field); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if field.Stem == nil || !*field.Stem {
+		t.Errorf("Expected to unmarshal stem true, unmarshalled %v", field.Stem)
+	}
+}
+
+func TestCollectionField_embed(t *testing.T) {
+	field := CollectionField{
+		Name: "embedding",
+		Type: "float[]",
+		Embed: &EmbedConfig{
+			From:        []string{"name", "description"},
+			ModelConfig: ModelConfig{ModelName: "openai/text-embedding-3-small", APIKey: "sk-secret"},
+		},
+	}
+	fieldJSON, err := json.Marshal(field)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expectedJSON := `{"name":"embedding","type":"float[]","facet":false,"embed":{"from":["name","description"],"model_config":{"model_name":"openai/text-embedding-3-small","api_key":"sk-secret"}}}`
+	if string(fieldJSON) != expectedJSON {
+		t.Errorf("Expected to marshal %s, marshalled %s", expectedJSON, fieldJSON)
+	}
+	if err := field.validate(); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	fieldJSON, _ = json.Marshal(CollectionField{Name: "name", Type: "string"})
+	if strings.Contains(string(fieldJSON), "embed") {
+		t.Errorf("Expected to omit embed when it is not set, marshalled %s", fieldJSON)
+	}
+	field.Type = "string"
+	if err := field.validate(); !errors.Is(err, ErrInvalidEmbedConfig) {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidEmbedConfig, err)
+	}
+	field.Type, field.Embed.From = "float[]", nil
+	if err := field.validate(); !errors.Is(err, ErrInvalidEmbedConfig) {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidEmbedConfig, err)
+	}
+}
+
+func TestCollectionField_options(t *testing.T) {
+	index := false
+	fields := []CollectionField{
+		{Name: "description", Type: "string", Optional: true, Index: &index},
+		{Name: "title", Type: "string", Sort: true, Infix: true, Locale: "ja"},
+		{Name: "embedding", Type: "float[]", NumDim: 384},
+		{Name: "name", Type: "string"},
+	}
+	expected := []string{
+		`{"name":"description","type":"string","facet":false,"optional":true,"index":false}`,
+		`{"name":"title","type":"string","facet":false,"sort":true,"infix":true,"locale":"ja"}`,
+		`{"name":"embedding","type":"float[]","facet":false,"num_dim":384}`,
+		`{"name":"name","type":"string","facet":false}`,
+	}
+	for i, field := range fields {
+		fieldJSON, err := json.Marshal(field)
+		if err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		if string(fieldJSON) != expected[i] {
+			t.Errorf("Expected to marshal %s, marshalled %s", expected[i], fieldJSON)
+		}
+	}
+}
+
+func TestCreateCollection_stemNotSupported(t *testing.T) {
+	stem := true
+	testData := CollectionSchema{
+		Name:   "companies",
+		Fields: []CollectionField{{Name: "num_employees", Type: "int32", Stem: &stem}},
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	_, err := client.CreateCollection(testData)
+	if !errors.Is(err, ErrStemNotSupported) {
+		t.Errorf("Expected to receive error %v, received error %v", ErrStemNotSupported, err)
+	}
+}
+
+func TestValidateCollectionSchema_duplicateFieldName(t *testing.T) {
+	testData := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "country", Type: "string"},
+			{Name: "country", Type: "string", Facet: true},
+		},
+	}
+	err := ValidateCollectionSchema(testData)
+	if !errors.Is(err, ErrDuplicateFieldName) {
+		t.Errorf("Expected to receive error %v, received error %v", ErrDuplicateFieldName, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "country") {
+		t.Errorf("Expected the error to list the duplicate field, received %v", err)
+	}
+	if err := ValidateCollectionSchema(testCollectionSchema); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestValidateCollectionName(t *testing.T) {
+	for _, name := range []string{"companies", "companies_2024", "new-collection"} {
+		if err := ValidateCollectionName(name); err != nil {
+			t.Errorf("Expected to receive no errors for %q, received %v", name, err)
+		}
+	}
+	for _, name := range []string{"", " ", "companies/2024", "companies?x=1", "com#panies"} {
+		if err := ValidateCollectionName(name); !errors.Is(err, ErrInvalidCollectionName) {
+			t.Errorf("Expected to receive error %v for %q, received %v", ErrInvalidCollectionName, name, err)
+		}
+	}
+}
+
+func TestCreateCollection_invalidName(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not make any request")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	testData := CollectionSchema{
+		Name:   "companies/2024",
+		Fields: []CollectionField{{Name: "name", Type: "string"}},
+	}
+	_, err := client.CreateCollection(testData)
+	if !errors.Is(err, ErrInvalidCollectionName) {
+		t.Errorf("Expected to receive error %v, received error %v", ErrInvalidCollectionName, err)
+	}
+}
+
 func TestCreateCollection_conflict(t *testing.T) {
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -153,6 +345,27 @@ func TestRetrieveCollection(t *testing.T) {
 	}
 }
 
+func TestRetrieveSchema(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		collectionJSON, _ := json.Marshal(&testCollection)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(collectionJSON)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	schema, err := client.RetrieveSchema(testCollection.Name)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !reflect.DeepEqual(*schema, testCollection.CollectionSchema) {
+		t.Errorf("Expected to receive %v, received %v", testCollection.CollectionSchema, *schema)
+	}
+}
+
 func TestRetrieveCollection_notFound(t *testing.T) {
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -207,3 +420,696 @@ func TestDeleteCollection_notFound(t *testing.T) {
 		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
 	}
 }
+
+func TestUpdateCollection(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPatch {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodPatch, req.Method)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		expectedBody := `{"fields":[{"name":"country","drop":true},{"name":"country","type":"string","facet":true}]}`
+		if string(body) != expectedBody {
+			t.Errorf("Expected to receive body %s, received %s", expectedBody, body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	fields := []CollectionFieldUpdate{
+		{CollectionField: CollectionField{Name: "country"}, Drop: true},
+		{CollectionField: CollectionField{Name: "country", Type: "string", Facet: true}},
+	}
+	collection, err := client.UpdateCollection("companies", fields)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if collection == nil || len(collection.Fields) != 2 {
+		t.Errorf("Expected to receive the updated fields, received %v", collection)
+	}
+}
+
+func TestUpdateCollection_badRequest(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Default sorting field cannot be dropped."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	fields := []CollectionFieldUpdate{{CollectionField: CollectionField{Name: "num_employees"}, Drop: true}}
+	_, err := client.UpdateCollection("companies", fields)
+	expected := &APIError{StatusCode: http.StatusBadRequest, Message: "Default sorting field cannot be dropped."}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("Expected to receive error %v, received %v", expected, err)
+	}
+}
+
+func TestCollectionSchemaHash(t *testing.T) {
+	schema := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "country", Type: "string", Facet: true},
+			{Name: "num_employees", Type: "int32"},
+		},
+		DefaultSortingField: "num_employees",
+	}
+	reordered := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "num_employees", Type: "int32"},
+			{Name: "name", Type: "string"},
+			{Name: "country", Type: "string", Facet: true},
+		},
+		DefaultSortingField: "num_employees",
+	}
+	if schema.Hash() != reordered.Hash() {
+		t.Errorf("Expected schemas with reordered fields to have the same hash, received %v and %v", schema.Hash(), reordered.Hash())
+	}
+	if schema.Fields[0].Name != "name" {
+		t.Errorf("Expected hashing to not reorder the schema fields, received %v", schema.Fields)
+	}
+	changed := reordered
+	changed.Fields = []CollectionField{
+		{Name: "num_employees", Type: "int32"},
+		{Name: "name", Type: "string"},
+		{Name: "country", Type: "string"},
+	}
+	if schema.Hash() == changed.Hash() {
+		t.Errorf("Expected schemas with different field flags to have different hashes")
+	}
+	index, stem := true, false
+	retrieved := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string", Index: &index, Stem: &stem},
+			{Name: "country", Type: "string", Facet: true, Index: &index, Stem: &stem},
+			{Name: "num_employees", Type: "int32", Sort: true, Index: &index, Stem: &stem},
+		},
+		DefaultSortingField: "num_employees",
+	}
+	if schema.Hash() != retrieved.Hash() {
+		t.Errorf("Expected a retrieved schema with the server defaults to have the same hash, received %v and %v", schema.Hash(), retrieved.Hash())
+	}
+}
+
+func TestResolvedFields(t *testing.T) {
+	jsonBody := `{
+		"name": "companies",
+		"num_documents": 1,
+		"fields": [
+			{"name": ".*", "type": "auto", "facet": false},
+			{"name": "name", "type": "string", "facet": false},
+			{"name": "num_employees", "type": "int64", "facet": false}
+		]
+	}`
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(jsonBody)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	fields, err := client.ResolvedFields("companies")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := []CollectionField{
+		{Name: "name", Type: "string"},
+		{Name: "num_employees", Type: "int64"},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, fields)
+	}
+}
+
+func TestRetrieveCollectionsByNames(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		name := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		if name == "missing" {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name": "` + name + `", "num_documents": 0, "fields": []}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	names := []string{"companies", "missing", "books"}
+	collections, errs := client.RetrieveCollectionsByNames(names)
+	if len(collections) != 2 || collections["companies"] == nil || collections["books"] == nil {
+		t.Errorf("Expected to receive the companies and books collections, received %v", collections)
+	}
+	if len(errs) != len(names) {
+		t.Fatalf("Expected to receive %v errors, received %v", len(names), len(errs))
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("Expected to receive no errors for the existing collections, received %v", errs)
+	}
+	if errs[1] != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, errs[1])
+	}
+}
+
+func TestRetrieveCollectionsByNames_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies", "num_documents": 0, "fields": []}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient:    mockClient,
+		masterNode:    testMasterNode,
+		maxConcurrent: 1,
+	}
+	names := []string{"companies", "books", "authors"}
+	collections, errs := client.RetrieveCollectionsByNames(names, WithContext(ctx))
+	if errs[0] != nil || collections["companies"] == nil {
+		t.Errorf("Expected to retrieve the collection requested before the cancellation, received %v", errs[0])
+	}
+	if !errors.Is(errs[1], context.Canceled) || !errors.Is(errs[2], context.Canceled) {
+		t.Errorf("Expected to receive %v for the remaining names, received %v", context.Canceled, errs[1:])
+	}
+}
+
+func TestCollectionSchemaClone(t *testing.T) {
+	index, stem := false, true
+	collection := Collection{
+		CollectionSchema: CollectionSchema{
+			Name: "companies",
+			Fields: []CollectionField{
+				{Name: "name", Type: "string"},
+				{Name: "num_employees", Type: "int32"},
+				{Name: "description", Type: "string", Index: &index, Stem: &stem},
+				{
+					Name: "embedding",
+					Type: "float[]",
+					Embed: &EmbedConfig{
+						From:        []string{"name"},
+						ModelConfig: ModelConfig{ModelName: "ts/all-MiniLM-L12-v2"},
+					},
+				},
+			},
+			DefaultSortingField: "num_employees",
+		},
+		NumDocuments: 10,
+	}
+	schema := collection.Schema()
+	if !reflect.DeepEqual(schema, collection.CollectionSchema) {
+		t.Errorf("Expected to receive %v, received %v", collection.CollectionSchema, schema)
+	}
+	clone := schema.Clone()
+	clone.Name = "companies_v2"
+	clone.Fields[0].Type = "string[]"
+	clone.Fields = append(clone.Fields, CollectionField{Name: "country", Type: "string"})
+	*clone.Fields[2].Index = true
+	*clone.Fields[2].Stem = false
+	clone.Fields[3].Embed.From[0] = "description"
+	clone.Fields[3].Embed.ModelConfig.ModelName = "openai/text-embedding-3-small"
+	if schema.Name != "companies" || schema.Fields[0].Type != "string" || len(schema.Fields) != 4 {
+		t.Errorf("Expected mutating the clone to not affect the original, received %v", schema)
+	}
+	if *schema.Fields[2].Index || !*schema.Fields[2].Stem {
+		t.Errorf("Expected mutating the options of the clone to not affect the original, received %v", schema.Fields[2])
+	}
+	if embed := schema.Fields[3].Embed; embed.From[0] != "name" || embed.ModelConfig.ModelName != "ts/all-MiniLM-L12-v2" {
+		t.Errorf("Expected mutating the embedding of the clone to not affect the original, received %v", embed)
+	}
+	schema.Fields[1].Type = "int64"
+	if collection.Fields[1].Type != "int32" {
+		t.Errorf("Expected mutating the schema to not affect the collection, received %v", collection.Fields)
+	}
+}
+
+func TestSchemaFromSampleJSON(t *testing.T) {
+	sample := []byte(`{
+		"id": "124",
+		"company_name": "Stark Industries",
+		"num_employees": 5215,
+		"revenue": 1.5e9,
+		"public": true,
+		"tags": ["defense", "energy"],
+		"ratings": [4, 4.5],
+		"founders": [],
+		"address": {"city": "New York"},
+		"parent": null
+	}`)
+	schema, err := SchemaFromSampleJSON("companies", sample)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expectedTypes := map[string]string{
+		"address":       "object",
+		"company_name":  "string",
+		"founders":      "auto",
+		"num_employees": "int64",
+		"parent":        "auto",
+		"public":        "bool",
+		"ratings":       "float[]",
+		"revenue":       "float",
+		"tags":          "string[]",
+	}
+	if schema.Name != "companies" || len(schema.Fields) != len(expectedTypes) {
+		t.Fatalf("Expected to infer %d fields, inferred %v", len(expectedTypes), schema.Fields)
+	}
+	for _, field := range schema.Fields {
+		if field.Type != expectedTypes[field.Name] {
+			t.Errorf("Expected field %s to have type %s, received %s", field.Name, expectedTypes[field.Name], field.Type)
+		}
+		if !field.Optional {
+			t.Errorf("Expected field %s to be optional", field.Name)
+		}
+	}
+	if !schema.EnableNestedFields {
+		t.Errorf("Expected the nested fields to be enabled for the address object")
+	}
+}
+
+func TestSchemaFromSampleJSON_invalid(t *testing.T) {
+	if _, err := SchemaFromSampleJSON("companies", []byte(`["not", "an", "object"]`)); err == nil {
+		t.Errorf("Expected to receive an error for a sample that is not an object")
+	}
+}
+
+func TestCreateCollection_schemaValidationError(t *testing.T) {
+	message := "Field `title` has an invalid locale `xx`."
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "` + message + `"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	testData := CollectionSchema{
+		Name:   "books",
+		Fields: []CollectionField{{Name: "title", Type: "string", Locale: "xx"}},
+	}
+	_, err := client.CreateCollection(testData)
+	var validationErr *SchemaValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected to receive a *SchemaValidationError, received %v", err)
+	}
+	expected := []ValidationError{{Field: "title", Reason: message}}
+	if !reflect.DeepEqual(validationErr.Fields, expected) {
+		t.Errorf("Expected to receive the validation errors %v, received %v", expected, validationErr.Fields)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the error to wrap the API error, received %v", err)
+	}
+}
+
+func TestValidateCollectionSchema_fieldTypes(t *testing.T) {
+	tests := []struct {
+		schema   CollectionSchema
+		expected error
+	}{
+		{CollectionSchema{Name: "books", Fields: []CollectionField{{Name: "title", Type: "strng"}}}, ErrInvalidFieldType},
+		{CollectionSchema{Name: "books", Fields: []CollectionField{{Name: "title", Type: "string"}}, DefaultSortingField: "title"}, ErrInvalidDefaultSortingField},
+		{CollectionSchema{Name: "books", Fields: []CollectionField{{Name: "title", Type: "string"}}, DefaultSortingField: "ratings_count"}, ErrInvalidDefaultSortingField},
+		{CollectionSchema{Name: "books", Fields: []CollectionField{{Name: "title", Type: "string"}, {Name: "ratings_count", Type: "int32"}}, DefaultSortingField: "ratings_count"}, nil},
+		{CollectionSchema{Name: "places", Fields: []CollectionField{{Name: "location", Type: "geopoint"}, {Name: ".*", Type: "auto"}}}, nil},
+	}
+	for _, test := range tests {
+		if err := test.schema.Validate(); !errors.Is(err, test.expected) {
+			t.Errorf("Expected to receive error %v for %+v, received %v", test.expected, test.schema, err)
+		}
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not send an invalid schema")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.CreateCollection(tests[0].schema); !errors.Is(err, ErrInvalidFieldType) {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidFieldType, err)
+	}
+}
+
+func TestValidateCollectionSchema_defaultSortingField(t *testing.T) {
+	testData := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "company_name", Type: "string"},
+			{Name: "num_employees", Type: "int32", Optional: true},
+		},
+		DefaultSortingField: "company_name",
+	}
+	err := ValidateCollectionSchema(testData)
+	if !errors.Is(err, ErrInvalidDefaultSortingField) {
+		t.Fatalf("Expected to receive error %v, received %v", ErrInvalidDefaultSortingField, err)
+	}
+	if !strings.Contains(err.Error(), "company_name has the type string") {
+		t.Errorf("Expected the error to name the field and its type, received %v", err)
+	}
+	testData.DefaultSortingField = "num_employees"
+	if err := ValidateCollectionSchema(testData); !errors.Is(err, ErrInvalidDefaultSortingField) {
+		t.Errorf("Expected to receive error %v for an optional field, received %v", ErrInvalidDefaultSortingField, err)
+	}
+	testData.Fields[1].Optional = false
+	if err := ValidateCollectionSchema(testData); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestCreateCollection_nestedFields(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		var schema map[string]interface{}
+		json.Unmarshal(body, &schema)
+		if schema["enable_nested_fields"] != true {
+			t.Errorf("Expected to enable the nested fields, received %s", body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	testData := CollectionSchema{
+		Name: "products",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "address", Type: "object"},
+			{Name: "address.city", Type: "string", Facet: true},
+			{Name: "variants", Type: "object[]", Optional: true},
+		},
+		EnableNestedFields: true,
+	}
+	collection, err := client.CreateCollection(testData)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !collection.EnableNestedFields || !reflect.DeepEqual(collection.Fields, testData.Fields) {
+		t.Errorf("Expected to receive the nested schema %+v, received %+v", testData, collection.CollectionSchema)
+	}
+	testData.EnableNestedFields = false
+	if _, err := client.CreateCollection(testData); !errors.Is(err, ErrNestedFieldsDisabled) {
+		t.Errorf("Expected to receive error %v, received %v", ErrNestedFieldsDisabled, err)
+	}
+}
+
+func TestRetrieveCollectionResolvingAlias(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/aliases/companies":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies", "collection_name": "companies_june11"}`)),
+			}, nil
+		case "/collections/companies_june11", "/collections/products":
+			name := strings.TrimPrefix(req.URL.Path, "/collections/")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name": "` + name + `", "num_documents": 10}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	tests := []struct {
+		name         string
+		resolvedName string
+	}{
+		{"companies", "companies_june11"},
+		{"products", "products"},
+	}
+	for _, test := range tests {
+		collection, resolvedName, err := client.RetrieveCollectionResolvingAlias(test.name)
+		if err != nil {
+			t.Fatalf("Expected to receive no errors, received %v", err)
+		}
+		if resolvedName != test.resolvedName || collection.Name != test.resolvedName {
+			t.Errorf("Expected to resolve %s to %s, received %s and %s", test.name, test.resolvedName, resolvedName, collection.Name)
+		}
+	}
+	if _, _, err := client.RetrieveCollectionResolvingAlias("missing"); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestSafeDeleteCollection(t *testing.T) {
+	var deleted bool
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			deleted = true
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies_june11"}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"aliases": [{"name": "companies", "collection_name": "companies_june11"}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	err := client.SafeDeleteCollection("companies_june11", false)
+	if !errors.Is(err, ErrCollectionAliased) {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionAliased, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "companies") {
+		t.Errorf("Expected the error to list the aliases, received %v", err)
+	}
+	if deleted {
+		t.Errorf("Expected to not delete an aliased collection")
+	}
+	if err := client.SafeDeleteCollection("companies_june11", true); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if !deleted {
+		t.Errorf("Expected to delete the collection when forced")
+	}
+	deleted = false
+	if err := client.SafeDeleteCollection("companies_june10", false); err != nil || !deleted {
+		t.Errorf("Expected to delete a collection without aliases, received %v", err)
+	}
+}
+
+func TestSafeDeleteCollection_aliasesError(t *testing.T) {
+	for _, statusCode := range []int{http.StatusForbidden, http.StatusBadGateway} {
+		var deleted bool
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodDelete {
+				deleted = true
+			}
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden"}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		var apiErr *APIError
+		if err := client.SafeDeleteCollection("companies_june11", false); !errors.As(err, &apiErr) || apiErr.StatusCode != statusCode {
+			t.Errorf("Expected to receive an *APIError with status %d, received %v", statusCode, err)
+		}
+		if deleted {
+			t.Errorf("Expected to not delete the collection when the aliases couldn't be listed")
+		}
+	}
+}
+
+func TestEnsureCollection(t *testing.T) {
+	index, stem := true, false
+	existing := Collection{CollectionSchema: CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string", Facet: true, Index: &index, Stem: &stem},
+			{Name: "num_employees", Type: "int32", Index: &index, Stem: &stem, Sort: true},
+			{Name: "founded", Type: "int32", Index: &index, Stem: &stem, Sort: true},
+		},
+	}}
+	var requests []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		if req.Method == http.MethodPost {
+			return &http.Response{
+				StatusCode: http.StatusConflict,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "A collection with name companies already exists."}`)),
+			}, nil
+		}
+		collectionJSON, _ := json.Marshal(existing)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(collectionJSON)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	schema := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string", Facet: true},
+			{Name: "num_employees", Type: "int32"},
+			{Name: "founded", Type: "int32"},
+		},
+	}
+	collection, err := client.EnsureCollection(schema)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !reflect.DeepEqual(*collection, existing) {
+		t.Errorf("Expected to receive the existing collection %+v, received %+v", existing, *collection)
+	}
+	if expected := []string{"POST /collections", "GET /collections/companies"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to make the requests %v, made %v", expected, requests)
+	}
+	schema.Fields = []CollectionField{
+		{Name: "name", Type: "string"},
+		{Name: "num_employees", Type: "int64"},
+		{Name: "website", Type: "string"},
+	}
+	collection, err = client.EnsureCollection(schema)
+	if !errors.Is(err, ErrSchemaMismatch) {
+		t.Fatalf("Expected to receive error %v, received %v", ErrSchemaMismatch, err)
+	}
+	for _, difference := range []string{"field name has other options", "field num_employees is int32 instead of int64", "extra field founded", "missing field website"} {
+		if !strings.Contains(err.Error(), difference) {
+			t.Errorf("Expected the error to list %q, received %v", difference, err)
+		}
+	}
+	if collection == nil || collection.Name != "companies" {
+		t.Errorf("Expected to receive the existing collection, received %+v", collection)
+	}
+}
+
+func TestCloneCollection(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			if req.URL.Path != "/collections/companies" {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"name": "companies",
+					"num_documents": 1250,
+					"fields": [{"name": "num_employees", "type": "int32", "facet": false}],
+					"default_sorting_field": "num_employees"
+				}`)),
+			}, nil
+		}
+		var schema CollectionSchema
+		json.NewDecoder(req.Body).Decode(&schema)
+		if schema.Name != "companies_v2" || schema.DefaultSortingField != "num_employees" || len(schema.Fields) != 1 {
+			t.Errorf("Expected to create the clone of the schema, received %+v", schema)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies_v2", "num_documents": 0}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	collection, err := client.CloneCollection("companies", "companies_v2")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if collection.Name != "companies_v2" || collection.NumDocuments != 0 {
+		t.Errorf("Expected to receive the empty clone, received %+v", collection)
+	}
+	if _, err := client.CloneCollection("missing", "missing_v2"); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestStreamCollections(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`[
+				{"name": "companies", "num_documents": 1250, "fields": []},
+				{"name": "products", "num_documents": 42, "fields": []}
+			]`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	collections, errs := client.StreamCollections()
+	var names []string
+	for collection := range collections {
+		names = append(names, collection.Name)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"companies", "products"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected to receive the collections %v, received %v", expected, names)
+	}
+}
+
+func TestStreamCollections_decodeError(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`[{"name": "companies"}, {"name": `)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	collections, errs := client.StreamCollections()
+	received := 0
+	for range collections {
+		received++
+	}
+	if err := <-errs; err == nil {
+		t.Errorf("Expected to receive the decode error")
+	}
+	if received != 1 {
+		t.Errorf("Expected to receive the collection decoded before the error, received %v", received)
+	}
+}
@@ -3,6 +3,9 @@ package typesense
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 )
 
 // DocumentResponse is the response returned with a
@@ -23,3 +26,215 @@ func (ds *DocumentResponse) UnmarshalDocument(document interface{}) error {
 	err := json.NewDecoder(bytes.NewReader(ds.Data)).Decode(&document)
 	return err
 }
+
+// NullHandling is how the empty values of the documents are sent to
+// Typesense when they are indexed or imported, see WithNullHandling.
+type NullHandling int
+
+const (
+	// SendNulls sends the documents as they marshal into JSON, nil
+	// pointers, slices and maps are sent as null. Typesense accepts
+	// null only for optional fields.
+	SendNulls NullHandling = iota
+
+	// OmitEmpty leaves out the top level fields of the documents that
+	// are null, empty strings, empty arrays or empty objects. Zeros and
+	// false are kept. Typesense accepts missing fields only for
+	// optional fields.
+	OmitEmpty
+
+	// CoerceToDefault replaces the nil pointers, slices and maps of
+	// struct documents with their zero values, e.g. 0, "" or [], so
+	// required fields always have a value. The nil pointers of
+	// recursive types, e.g. the parent of a tree node, are sent as null.
+	// The nil values of map documents are sent as null, their type is
+	// unknown.
+	CoerceToDefault
+)
+
+// marshalNewDocument marshals the document of a create, an upsert or
+// an import like marshalDocument, deriving its id from the id field of
+// the client, see WithIDField.
+func (c *Client) marshalNewDocument(document interface{}) ([]byte, error) {
+	documentJSON, err := c.marshalDocument(document)
+	if err != nil || c.idField == "" {
+		return documentJSON, err
+	}
+	return deriveID(documentJSON, c.idField)
+}
+
+// marshalDocument marshals the document into JSON applying the client
+// null handling and the field name transformer to the struct fields
+// without a json tag name.
+func (c *Client) marshalDocument(document interface{}) ([]byte, error) {
+	if c.nullHandling == CoerceToDefault {
+		document = coerceToDefault(document)
+	}
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	if c.nullHandling == OmitEmpty {
+		if documentJSON, err = omitEmptyFields(documentJSON); err != nil {
+			return nil, err
+		}
+	}
+	if c.fieldNameTransformer != nil {
+		if documentJSON, err = c.transformFieldNames(document, documentJSON); err != nil {
+			return nil, err
+		}
+	}
+	return documentJSON, nil
+}
+
+// transformFieldNames applies the field name transformer of the client
+// to the fields of the struct document without a json tag name.
+func (c *Client) transformFieldNames(document interface{}, documentJSON []byte) ([]byte, error) {
+	documentType := reflect.TypeOf(document)
+	for documentType != nil && documentType.Kind() == reflect.Ptr {
+		documentType = documentType.Elem()
+	}
+	if documentType == nil || documentType.Kind() != reflect.Struct {
+		return documentJSON, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(documentJSON, &fields); err != nil {
+		return nil, err
+	}
+	taggedNames := make(map[string]bool)
+	collectTaggedNames(documentType, taggedNames)
+	transformed := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		if !taggedNames[name] {
+			name = c.fieldNameTransformer(name)
+		}
+		transformed[name] = value
+	}
+	return json.Marshal(transformed)
+}
+
+// collectTaggedNames collects the field names explicitly set by json
+// tags in the struct type, including the ones of embedded structs.
+func collectTaggedNames(structType reflect.Type, names map[string]bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tagName != "" && tagName != "-" {
+			names[tagName] = true
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			collectTaggedNames(fieldType, names)
+		}
+	}
+}
+
+// deriveID copies the value of the id field into the id of the JSON
+// object when it has no id, formatting numbers as strings since
+// Typesense ids are strings.
+func deriveID(documentJSON []byte, idField string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(documentJSON, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["id"]; ok {
+		return documentJSON, nil
+	}
+	value, ok := fields[idField]
+	if !ok || string(value) == "null" {
+		return nil, fmt.Errorf("%w: %s", ErrIDFieldNotFound, idField)
+	}
+	if value[0] != '"' {
+		value, _ = json.Marshal(string(value))
+	}
+	fields["id"] = value
+	return json.Marshal(fields)
+}
+
+// omitEmptyFields removes the null and empty top level fields of the
+// JSON object, other JSON values are returned as is.
+func omitEmptyFields(documentJSON []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(documentJSON, &fields); err != nil {
+		return documentJSON, nil
+	}
+	for name, value := range fields {
+		switch string(value) {
+		case "null", `""`, "[]", "{}":
+			delete(fields, name)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// coerceToDefault returns a copy of the struct document with its nil
+// pointers, slices and maps replaced by zero values, documents of other
+// kinds are returned as is.
+func coerceToDefault(document interface{}) interface{} {
+	value := reflect.ValueOf(document)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return document
+	}
+	coerced := reflect.New(value.Type()).Elem()
+	coerced.Set(value)
+	coerceFields(coerced, map[reflect.Type]bool{value.Type(): true})
+	return coerced.Interface()
+}
+
+// coerceFields replaces the nil fields of the settable struct value with
+// zero values, recursively into its struct fields. The pointers to the
+// struct types already on the recursion path, e.g. the parent of a
+// recursive type, are left as they are so the recursion ends.
+func coerceFields(value reflect.Value, path map[reflect.Type]bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Ptr:
+			elemType := field.Type().Elem()
+			if elemType.Kind() == reflect.Struct && path[elemType] {
+				continue
+			}
+			if field.IsNil() {
+				field.Set(reflect.New(elemType))
+			} else {
+				copied := reflect.New(elemType)
+				copied.Elem().Set(field.Elem())
+				field.Set(copied)
+			}
+			if elemType.Kind() == reflect.Struct {
+				coerceStruct(field.Elem(), path)
+			}
+		case reflect.Slice:
+			if field.IsNil() {
+				field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+			}
+		case reflect.Map:
+			if field.IsNil() {
+				field.Set(reflect.MakeMap(field.Type()))
+			}
+		case reflect.Struct:
+			coerceStruct(field, path)
+		}
+	}
+}
+
+// coerceStruct coerces the fields of the struct value with its type
+// added to the recursion path.
+func coerceStruct(value reflect.Value, path map[reflect.Type]bool) {
+	if path[value.Type()] {
+		return
+	}
+	path[value.Type()] = true
+	coerceFields(value, path)
+	delete(path, value.Type())
+}
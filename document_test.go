@@ -1,10 +1,15 @@
 package typesense
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"unicode"
 )
 
 func TestUnmarshalDocument(t *testing.T) {
@@ -42,3 +47,137 @@ func TestUnmarshalDocument_withError(t *testing.T) {
 		t.Errorf("Expected to receive error %v, received %v", errDocumentNotFound, err)
 	}
 }
+
+func TestMarshalDocument_fieldNameTransformer(t *testing.T) {
+	type company struct {
+		CompanyName  string
+		NumEmployees int
+		Country      string `json:"country_code"`
+	}
+	var indexedDocument map[string]interface{}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &indexedDocument); err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	client := NewClient(testMasterNode, WithFieldNameTransformer(func(name string) string {
+		var snake strings.Builder
+		for i, r := range name {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					snake.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			snake.WriteRune(r)
+		}
+		return snake.String()
+	}))
+	client.httpClient = mockClient
+	documentResp := client.IndexDocument(collectionNameTest, company{
+		CompanyName:  "Stark Industries",
+		NumEmployees: 5215,
+		Country:      "US",
+	})
+	if documentResp.Error != nil {
+		t.Errorf("Expected to receive no errors, received %v", documentResp.Error)
+	}
+	expected := map[string]interface{}{
+		"company_name":  "Stark Industries",
+		"num_employees": float64(5215),
+		"country_code":  "US",
+	}
+	if !reflect.DeepEqual(indexedDocument, expected) {
+		t.Errorf("Expected to index %v, indexed %v", expected, indexedDocument)
+	}
+}
+
+func TestMarshalDocument_nullHandling(t *testing.T) {
+	type address struct {
+		City *string `json:"city"`
+	}
+	type company struct {
+		Name      string            `json:"name"`
+		Employees *int              `json:"num_employees"`
+		Tags      []string          `json:"tags"`
+		Metadata  map[string]string `json:"metadata"`
+		Public    bool              `json:"public"`
+		Address   *address          `json:"address"`
+	}
+	document := company{Name: "Stark Industries"}
+	tests := []struct {
+		mode     NullHandling
+		expected string
+	}{
+		{SendNulls, `{"name":"Stark Industries","num_employees":null,"tags":null,"metadata":null,"public":false,"address":null}`},
+		{OmitEmpty, `{"name":"Stark Industries","public":false}`},
+		{CoerceToDefault, `{"name":"Stark Industries","num_employees":0,"tags":[],"metadata":{},"public":false,"address":{"city":""}}`},
+	}
+	for _, test := range tests {
+		client := NewClient(testMasterNode, WithNullHandling(test.mode))
+		documentJSON, err := client.marshalDocument(&document)
+		if err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		if string(documentJSON) != test.expected {
+			t.Errorf("Expected to marshal %s, marshalled %s", test.expected, documentJSON)
+		}
+	}
+	if document.Employees != nil || document.Tags != nil {
+		t.Errorf("Expected to not modify the document, received %+v", document)
+	}
+}
+
+func TestMarshalDocument_coerceRecursiveType(t *testing.T) {
+	type cat struct {
+		Name   string `json:"name"`
+		Tags   []string
+		Parent *cat `json:"parent"`
+	}
+	client := NewClient(testMasterNode, WithNullHandling(CoerceToDefault))
+	documentJSON, err := client.marshalDocument(cat{Name: "Tom"})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if expected := `{"name":"Tom","Tags":[],"parent":null}`; string(documentJSON) != expected {
+		t.Errorf("Expected to marshal %s, marshalled %s", expected, documentJSON)
+	}
+	documentJSON, _ = client.marshalDocument(cat{Name: "Tom", Parent: &cat{Name: "Felix"}})
+	if expected := `{"name":"Tom","Tags":[],"parent":{"name":"Felix","Tags":null,"parent":null}}`; string(documentJSON) != expected {
+		t.Errorf("Expected to marshal %s, marshalled %s", expected, documentJSON)
+	}
+}
+
+func TestMarshalDocument_idField(t *testing.T) {
+	type product struct {
+		SKU  int    `json:"sku"`
+		Name string `json:"name"`
+	}
+	client := NewClient(testMasterNode, WithIDField("sku"))
+	documentJSON, err := client.marshalNewDocument(product{SKU: 1042, Name: "Phone"})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := `{"id":"1042","name":"Phone","sku":1042}`; string(documentJSON) != expected {
+		t.Errorf("Expected to marshal %s, marshalled %s", expected, documentJSON)
+	}
+	documentJSON, _ = client.marshalNewDocument(map[string]interface{}{"id": "p1", "sku": 1042})
+	if expected := `{"id":"p1","sku":1042}`; string(documentJSON) != expected {
+		t.Errorf("Expected to keep the id of the document %s, marshalled %s", expected, documentJSON)
+	}
+	if _, err := client.marshalNewDocument(map[string]interface{}{"name": "Phone"}); !errors.Is(err, ErrIDFieldNotFound) {
+		t.Errorf("Expected to receive error %v, received %v", ErrIDFieldNotFound, err)
+	}
+	documentJSON, err = client.marshalDocument(map[string]interface{}{"name": "Phone"})
+	if err != nil {
+		t.Errorf("Expected partial updates to not need the id field, received %v", err)
+	}
+	if expected := `{"name":"Phone"}`; string(documentJSON) != expected {
+		t.Errorf("Expected to marshal %s, marshalled %s", expected, documentJSON)
+	}
+}
@@ -1,30 +1,248 @@
 package typesense
 
 import (
+	"encoding/csv"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// wildcardQuery is the query that matches all documents.
+const wildcardQuery = "*"
+
 // SearchResponse is the default Typesense response for a serch.
 type SearchResponse struct {
-	FacetCounts []FacetCount      `json:"facet_counts"`
-	Found       int               `json:"found"`
-	Hits        []SearchResultHit `json:"hits"`
+	FacetCounts  []FacetCount      `json:"facet_counts"`
+	Found        int               `json:"found"`
+	Hits         []SearchResultHit `json:"hits"`
+	SearchTimeMs int               `json:"search_time_ms"`
+
+	// GroupedHits are the hits grouped by the GroupBy fields, when the
+	// search is grouped the hits are only returned in their groups and
+	// Hits is empty.
+	GroupedHits []GroupedHit `json:"grouped_hits"`
+
+	// FoundGroups is the number of groups found when the search is
+	// grouped with GroupBy, in that case Found is the number of
+	// documents found across all groups.
+	FoundGroups int `json:"-"`
+
+	// Error is the error message of a search of a multi search, the
+	// other fields are empty when it is set.
+	Error string `json:"error,omitempty"`
+
+	// Code is the HTTP status code of the error of a search of a multi
+	// search, e.g. 404 when its collection doesn't exist.
+	Code int `json:"code,omitempty"`
+
+	// Conversation is the answer of a conversational search.
+	Conversation *Conversation `json:"conversation,omitempty"`
+
+	// RelaxedQuery whether Typesense dropped tokens of the query to find
+	// some of the hits, e.g. to show "showing results for" in a UI. It is
+	// derived from the matched tokens of the hits, so it is false when
+	// the response has no hits or no text match info.
+	RelaxedQuery bool `json:"-"`
+
+	// RequestDuration is the wall-clock time of the search as seen by
+	// the client, including the retries and the network, to compare
+	// with the time spent by Typesense in SearchTimeMs. A result served
+	// from the result cache keeps the duration of its original search.
+	RequestDuration time.Duration `json:"-"`
+}
+
+// Conversation is the answer of the conversation model to a
+// conversational search, the ConversationID is used to continue the
+// conversation with follow-up questions.
+type Conversation struct {
+	ConversationID string `json:"conversation_id"`
+	Answer         string `json:"answer"`
+}
+
+// UnmarshalJSON decodes the search response. Grouped responses report
+// the number of groups in found and the number of documents in
+// found_docs, they are decoded into FoundGroups and Found respectively.
+func (r *SearchResponse) UnmarshalJSON(data []byte) error {
+	type searchResponse SearchResponse
+	var resp struct {
+		searchResponse
+		FoundDocs     *int `json:"found_docs"`
+		RequestParams struct {
+			Q string `json:"q"`
+		} `json:"request_params"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	*r = SearchResponse(resp.searchResponse)
+	if resp.FoundDocs != nil {
+		r.FoundGroups = r.Found
+		r.Found = *resp.FoundDocs
+	}
+	r.RelaxedQuery = r.relaxedQuery(len(strings.Fields(resp.RequestParams.Q)))
+	return nil
+}
+
+// relaxedQuery reports whether a hit of the response matched fewer
+// tokens than the queryTokens of the query.
+func (r *SearchResponse) relaxedQuery(queryTokens int) bool {
+	relaxed := func(hits []SearchResultHit) bool {
+		for _, hit := range hits {
+			if hit.TextMatchInfo != nil && hit.TextMatchInfo.TokensMatched < queryTokens {
+				return true
+			}
+		}
+		return false
+	}
+	if relaxed(r.Hits) {
+		return true
+	}
+	for _, group := range r.GroupedHits {
+		if relaxed(group.Hits) {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalPages returns the number of pages of perPage results, the
+// results are the groups for a grouped search. It is zero when perPage
+// is not positive.
+func (r *SearchResponse) TotalPages(perPage int) int {
+	if perPage <= 0 {
+		return 0
+	}
+	found := r.Found
+	if r.FoundGroups > 0 {
+		found = r.FoundGroups
+	}
+	return (found + perPage - 1) / perPage
+}
+
+// HasNextPage reports whether there are results after the page, the
+// pages start at 1.
+func (r *SearchResponse) HasNextPage(page, perPage int) bool {
+	return page < r.TotalPages(perPage)
+}
+
+// NextPageParams returns a copy of the search parameters of the
+// response for its next page, a search without Page is on the first
+// page. The slices of the parameters are shared with the copy.
+func (r *SearchResponse) NextPageParams(current SearchParameters) SearchParameters {
+	next := current
+	page := 2
+	if current.Page != nil {
+		page = *current.Page + 1
+	}
+	next.Page = &page
+	return next
+}
+
+// GroupedHit is a group of hits of a grouped search, the group key
+// holds the values of the GroupBy fields shared by the hits.
+type GroupedHit struct {
+	GroupKey []interface{}     `json:"group_key"`
+	Found    int               `json:"found"`
+	Hits     []SearchResultHit `json:"hits"`
 }
 
 // FacetCount is the representation of a Typesense facet count.
 type FacetCount struct {
-	FieldName string `json:"field_name"`
-	Counts    []struct {
-		Count int    `json:"count"`
-		Value string `json:"value"`
-	} `json:"counts"`
+	FieldName string       `json:"field_name"`
+	Counts    []FacetValue `json:"counts"`
+
+	// Stats are the statistics of the values of a numeric facet field,
+	// they are empty for the other fields.
+	Stats FacetStats `json:"stats"`
+}
+
+// FacetValue is a value of a facet field with the number of documents
+// that have it. When the search has a FacetQuery the matched part of
+// the value is highlighted in Highlighted.
+type FacetValue struct {
+	Count       int    `json:"count"`
+	Value       string `json:"value"`
+	Highlighted string `json:"highlighted"`
+}
+
+// FacetStats are the statistics of the values of a numeric facet field.
+type FacetStats struct {
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+	Avg         float64 `json:"avg"`
+	Sum         float64 `json:"sum"`
+	TotalValues int     `json:"total_values"`
+}
+
+// RangeFacetBucket is a bucket of a range facet, it holds the range
+// label with its bounds and the number of documents in the range.
+type RangeFacetBucket struct {
+	Label string
+	Min   float64
+	Max   float64
+	Count int
+}
+
+// RangeFacet builds a range facet expression to be used in the
+// facet_by search option, e.g. price(Budget:[0, 50], Premium:[50, 100]).
+// The ranges are keyed by their label and each value holds the lower
+// and upper bounds of the range.
+func RangeFacet(field string, ranges map[string][2]float64) string {
+	labels := sortedRangeLabels(ranges)
+	expressions := make([]string, len(labels))
+	for i, label := range labels {
+		bounds := ranges[label]
+		expressions[i] = fmt.Sprintf(
+			"%s:[%s, %s]",
+			label,
+			strconv.FormatFloat(bounds[0], 'f', -1, 64),
+			strconv.FormatFloat(bounds[1], 'f', -1, 64),
+		)
+	}
+	return fmt.Sprintf("%s(%s)", field, strings.Join(expressions, ", "))
+}
+
+// RangeBuckets returns the counts of a range facet as buckets using the
+// same ranges given to RangeFacet. Ranges without any documents are
+// returned with a zero count.
+func (fc FacetCount) RangeBuckets(ranges map[string][2]float64) []RangeFacetBucket {
+	counts := make(map[string]int, len(fc.Counts))
+	for _, count := range fc.Counts {
+		counts[count.Value] = count.Count
+	}
+	labels := sortedRangeLabels(ranges)
+	buckets := make([]RangeFacetBucket, len(labels))
+	for i, label := range labels {
+		buckets[i] = RangeFacetBucket{
+			Label: label,
+			Min:   ranges[label][0],
+			Max:   ranges[label][1],
+			Count: counts[label],
+		}
+	}
+	return buckets
+}
+
+func sortedRangeLabels(ranges map[string][2]float64) []string {
+	labels := make([]string, 0, len(ranges))
+	for label := range ranges {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := ranges[labels[i]], ranges[labels[j]]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return labels[i] < labels[j]
+	})
+	return labels
 }
 
 // SearchResultHit represents a Typesense search result hit. Every
@@ -32,6 +250,34 @@ type FacetCount struct {
 type SearchResultHit struct {
 	Highlights []SearchHighlight      `json:"highlights"`
 	Document   map[string]interface{} `json:"document"`
+
+	// GeoDistanceMeters is the distance in meters of the document to
+	// the point of a geo search sorted by distance, keyed by the geo
+	// field name. It is nil for the other searches.
+	GeoDistanceMeters map[string]int `json:"geo_distance_meters,omitempty"`
+
+	// VectorDistance is the distance of the document to the vector of a
+	// vector search, zero for the other searches.
+	VectorDistance float64 `json:"vector_distance,omitempty"`
+
+	// TextMatchInfo is how the document matched the text of the query,
+	// nil for wildcard and vector searches.
+	TextMatchInfo *TextMatchInfo `json:"text_match_info,omitempty"`
+
+	// Curated whether the document was included in the results by an
+	// override, see Override, rather than matched by the query.
+	Curated bool `json:"curated,omitempty"`
+}
+
+// TextMatchInfo is how the document of a hit matched the text of the
+// query.
+type TextMatchInfo struct {
+	// TokensMatched is the number of tokens of the query matched by
+	// the document, fewer than the tokens of the query when Typesense
+	// dropped tokens to find it.
+	TokensMatched   int `json:"tokens_matched"`
+	FieldsMatched   int `json:"fields_matched"`
+	TypoPrefixScore int `json:"typo_prefix_score"`
 }
 
 // SearchHighlight represents the highlight of texts in the
@@ -41,16 +287,47 @@ type SearchHighlight struct {
 	Snippet  string   `json:"snippet"`
 	Snippets []string `json:"snippets"`
 	Indices  []int    `json:"indices"`
+
+	// MatchedTokens are the tokens of the field that matched the query,
+	// for array fields the tokens of all the matched elements.
+	MatchedTokens []string `json:"matched_tokens"`
+}
+
+// UnmarshalJSON decodes the highlight, flattening the matched tokens of
+// array fields that Typesense returns per element.
+func (h *SearchHighlight) UnmarshalJSON(data []byte) error {
+	type searchHighlight SearchHighlight
+	var highlight struct {
+		searchHighlight
+		MatchedTokens []json.RawMessage `json:"matched_tokens"`
+	}
+	if err := json.Unmarshal(data, &highlight); err != nil {
+		return err
+	}
+	*h = SearchHighlight(highlight.searchHighlight)
+	for _, token := range highlight.MatchedTokens {
+		var tokens []string
+		if err := json.Unmarshal(token, &tokens); err == nil {
+			h.MatchedTokens = append(h.MatchedTokens, tokens...)
+			continue
+		}
+		var single string
+		if err := json.Unmarshal(token, &single); err != nil {
+			return err
+		}
+		h.MatchedTokens = append(h.MatchedTokens, single)
+	}
+	return nil
 }
 
-// SearchOptions is all options that will be used to create
+// SearchParameters is all parameters that will be used to create
 // a form url encoded to search in Typesense. More information
 // about the values can be found at https://typesense.org/docs/0.14.0/api/#search-collection.
-type SearchOptions struct {
-	// Query text to search for.
-	Query string `url:"q"`
+type SearchParameters struct {
+	// Q text to search for, required.
+	Q string
 
-	// QueryBy represents fields to query_by.
+	// QueryBy represents fields to query_by, required.
 	QueryBy []string
 
 	// MaxHits is the max number of hits for the query search, value
@@ -60,6 +337,16 @@ type SearchOptions struct {
 	// Prefix whether the query should be treated as a prefix or not.
 	Prefix *bool
 
+	// PrefixByField whether the query should be treated as a prefix in
+	// each of the QueryBy fields, e.g. only in the title. It must have
+	// one value per QueryBy field and takes precedence over Prefix.
+	PrefixByField []bool
+
+	// QueryByWeights the weights of the QueryBy fields in the ranking,
+	// e.g. to boost the matches of the title over the body. It must
+	// have one weight per QueryBy field.
+	QueryByWeights []int
+
 	// FilterBy represents filter conditions for refining your search
 	// results.
 	FilterBy []string
@@ -75,7 +362,10 @@ type SearchOptions struct {
 	MaxFacetValues *int
 
 	// FacetQuery filter facet values by this paremeter, only values that
-	// match the facet value will be matched.
+	// match the facet value will be matched. It has the form
+	// field:prefix, e.g. "category:shoe" to search within the values
+	// of the category facet, the matched part of each returned value
+	// is highlighted in the Highlighted of its facet count.
 	FacetQuery *string
 
 	// NumTypos number of typographical errors (1 or 2) that would be
@@ -89,7 +379,8 @@ type SearchOptions struct {
 	PerPage *int
 
 	// GroupBy aggregate search results by groups, groups must be a
-	// facet field.
+	// facet field. When it is set the hits are returned in the
+	// GroupedHits of the response instead of its Hits.
 	GroupBy []string
 
 	// GroupLimit maximum number of hits to return for every group
@@ -100,12 +391,37 @@ type SearchOptions struct {
 	IncludeFields []string
 
 	// ExcludeFields list of fields from the document to exclude in the search result.
+	// When both are set, Typesense applies the exclusion after the inclusion.
 	ExcludeFields []string
 
+	// ExcludeResponseMetadata asks Typesense to leave the out_of and
+	// search_time_ms metadata out of the response, adding them to the
+	// excluded fields. The other metadata of the response, e.g.
+	// request_params, is not decoded into SearchResponse and is skipped.
+	ExcludeResponseMetadata bool
+
 	// HighlightFullFields list of fields which should be highlighted fully without snippeting.
 	// Default is all fields will be snipped.
 	HighlightFullFields []string
 
+	// HighlightFields list of fields which should be highlighted, it
+	// takes precedence over AutoHighlightFields.
+	// Default is all the QueryBy fields.
+	HighlightFields []string
+
+	// HighlightStartTag the tag inserted before the highlighted tokens.
+	// Default is <mark>.
+	HighlightStartTag string
+
+	// HighlightEndTag the tag inserted after the highlighted tokens.
+	// Default is </mark>.
+	HighlightEndTag string
+
+	// HighlightAffixNumTokens the number of tokens surrounding the
+	// highlighted tokens in the snippet.
+	// Default value is 4.
+	HighlightAffixNumTokens *int
+
 	// SnippetThreshold Field values under this length will be fully highlighted, instead
 	// of showing a snippet of relevant portion.
 	// Default value is 30.
@@ -128,30 +444,266 @@ type SearchOptions struct {
 
 	// HiddenHits list of records to unconditionally hide from search results.
 	Hiddenhits []string
+
+	// VectorQuery nearest neighbors query on a vector field, it can be
+	// built with NewVectorQuery. Q is not required when it is set, a
+	// vector only search uses the wildcard query.
+	VectorQuery string
+
+	// UseCache whether the search result should be served from and
+	// stored in the server cache.
+	UseCache bool
+
+	// CacheTTL the number of seconds the result of the search is kept
+	// in the server cache, it is only sent when UseCache is set.
+	// Default value is 60.
+	CacheTTL int
+
+	// DropTokensMode which tokens are dropped first when relaxing the
+	// query, one of right_to_left, left_to_right or both_sides:N, where
+	// N is the maximum number of tokens of the query for both sides to
+	// be tried. Default value is right_to_left.
+	DropTokensMode string
+
+	// AutoHighlightFields when enabled and IncludeFields is set, only the
+	// QueryBy fields that are also included in the result are highlighted,
+	// since highlighting fields that are not returned is wasted work. If
+	// none of the QueryBy fields is included highlighting is disabled.
+	AutoHighlightFields bool
+
+	// EnableTyposForNumericalTokens whether typo tolerance is applied on
+	// numerical tokens of the query, disable it to avoid matching wrong
+	// identifiers like SKUs or phone numbers. Default value is true.
+	EnableTyposForNumericalTokens *bool
+
+	// EnableSynonyms whether the synonyms of the collection are applied
+	// to the query, disable it to debug whether synonyms are affecting
+	// the results. Default value is true.
+	EnableSynonyms *bool
+
+	// Collection is the name of the collection to search, only used
+	// by MultiSearch, where each search may target a different
+	// collection.
+	Collection string
+
+	// Conversation whether the search is a conversational search, the
+	// answer of the conversation model is returned in the Conversation
+	// of the response.
+	Conversation bool
+
+	// ConversationModelID is the id of the conversation model used by
+	// a conversational search.
+	ConversationModelID string
+
+	// ConversationID is the id of a previous conversation to continue
+	// with a follow-up question.
+	ConversationID string
+
+	// EnableHighlightV1 whether the highlights are returned in the
+	// deprecated v1 format, the highlights field of the hits. When it is
+	// not set the default of the client is used, see WithHighlightV1.
+	EnableHighlightV1 *bool
+
+	// MaxExtraPrefix is the maximum number of symbols before a query
+	// token that can be matched, e.g. with infix search.
+	MaxExtraPrefix *int
+
+	// MaxExtraSuffix is the maximum number of symbols after a query
+	// token that can be matched, e.g. with infix search.
+	MaxExtraSuffix *int
+
+	// SplitJoinTokens whether the query tokens are split and joined to
+	// find more results, e.g. "basketball" matching "basket ball", one
+	// of "off", "fallback" or "always". Default value is "fallback".
+	SplitJoinTokens string
+
+	// Preset is the name of a preset whose parameters are applied to
+	// the search, the parameters of the search override them. QueryBy
+	// is not required when it is set, it may come from the preset.
+	Preset string
+
+	// FacetStrategy how the facet counts are computed, one of
+	// exhaustive, top_values or automatic. top_values trades the
+	// accuracy of the counts for speed on high cardinality facets.
+	// Default value is automatic.
+	FacetStrategy string
+
+	// EnableAnalytics whether the search is captured by the analytics
+	// rules, set it to false for internal searches that shouldn't count
+	// in the popular queries.
+	// Default is the server default, true.
+	EnableAnalytics *bool
+
+	// ExhaustiveSearch whether all the variations of the tokens are
+	// considered instead of stopping once enough results are found, e.g.
+	// to have exact facet counts on large result sets. It is slower.
+	// Default is false.
+	ExhaustiveSearch *bool
+
+	// MinLen1Typo the minimum length of a word for a typo to be
+	// corrected. Default value is 4.
+	MinLen1Typo *int
+
+	// MinLen2Typo the minimum length of a word for two typos to be
+	// corrected. Default value is 7.
+	MinLen2Typo *int
+
+	// Infix the infix search mode of each of the QueryBy fields, one of
+	// off, always or fallback, e.g. to match "456" inside "ABC-456-789".
+	// The fields must be indexed with Infix. It must have one mode per
+	// QueryBy field.
+	Infix []string
+
+	// PrioritizeExactMatch whether the documents that match the query
+	// exactly rank above the other documents with the same text match
+	// score, e.g. so an exact title match always ranks first.
+	// Default is true.
+	PrioritizeExactMatch *bool
+
+	// PrioritizeTokenPosition whether the documents that match the
+	// query in their first tokens rank above the other documents with
+	// the same text match score.
+	// Default is false.
+	PrioritizeTokenPosition *bool
+
+	// TextMatchType how the text match score of a document is computed
+	// from the scores of its QueryBy fields, one of max_score,
+	// max_weight or sum_score.
+	// Default is max_score.
+	TextMatchType string
+
+	// LimitHits caps the total number of hits that can be fetched for
+	// the search across all pages, e.g. a page past the limit returns
+	// no hits. It doesn't bound the work of the search itself, see
+	// MaxCandidates.
+	// Default is no limit.
+	LimitHits int
+
+	// MaxCandidates bounds the number of candidate tokens considered
+	// for each token of the query when looking for prefix and typo
+	// matches, bounding the work of expensive searches. Unlike
+	// LimitHits it doesn't cap the number of hits.
+	// Default is 4, or 10000 when ExhaustiveSearch is set.
+	MaxCandidates int
+
+	// FilterCuratedHits whether the FilterBy applies to the hits pinned
+	// by the overrides of the collection, so a curated hit that doesn't
+	// match the filter is dropped.
+	// Default is false.
+	FilterCuratedHits *bool
+
+	// EnableOverrides whether the overrides of the collection are
+	// applied, set it to false to compare the results without curation.
+	// Default is true.
+	EnableOverrides *bool
+
+	// SynonymPrefix whether the synonyms are matched on the prefix of
+	// the last token of the query, like the query itself with Prefix.
+	// Default is false.
+	SynonymPrefix *bool
+}
+
+// PhraseQuery wraps the text in double quotes so Typesense matches it
+// as an exact phrase instead of as separate tokens. Typesense has no
+// escaping for quotes inside a phrase, so the double quotes of the text
+// are removed.
+func PhraseQuery(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, "") + `"`
 }
 
-func (opts *SearchOptions) encodeForm() (string, error) {
+// RecallProfile returns the search parameters of a named relevance
+// posture, presetting NumTypos, TypoTokensThreshold,
+// DropTokensThreshold and SplitJoinTokens together:
+//
+//   - "strict" only matches the query as typed, without typos,
+//     dropped tokens or split and joined tokens.
+//   - "balanced" uses the Typesense defaults.
+//   - "fuzzy" looks for typos, drops tokens and splits and joins
+//     tokens aggressively to maximize recall.
+//
+// The other parameters, e.g. Q and QueryBy, must be set on the returned
+// parameters. An unknown profile returns empty parameters.
+func RecallProfile(profile string) SearchParameters {
+	preset := func(numTypos, typoTokensThreshold, dropTokensThreshold int, splitJoinTokens string) SearchParameters {
+		return SearchParameters{
+			NumTypos:            &numTypos,
+			TypoTokensThreshold: &typoTokensThreshold,
+			DropTokensThreshold: &dropTokensThreshold,
+			SplitJoinTokens:     splitJoinTokens,
+		}
+	}
+	switch profile {
+	case "strict":
+		return preset(0, 0, 0, "off")
+	case "balanced":
+		return preset(2, 1, 1, "fallback")
+	case "fuzzy":
+		return preset(2, 100, 100, "always")
+	}
+	return SearchParameters{}
+}
+
+func (opts *SearchParameters) encodeForm() (string, error) {
 	data := url.Values{}
-	if opts.Query == "" {
-		return "", ErrQueryRequired
+	q := opts.Q
+	if q == "" && opts.VectorQuery != "" {
+		// A vector only search matches all the documents by the text.
+		q = wildcardQuery
+	} else if q == "" {
+		return "", ErrSearchQueryRequired
 	}
-	data.Set("q", opts.Query)
-	if opts.QueryBy == nil || len(opts.QueryBy) == 0 {
+	data.Set("q", q)
+	if opts.QueryBy != nil && len(opts.QueryBy) > 0 {
+		queryBy := strings.Join(opts.QueryBy, ",")
+		data.Set("query_by", queryBy)
+	} else if q != wildcardQuery && opts.Preset == "" {
 		return "", ErrQueryByRequired
 	}
-	queryBy := strings.Join(opts.QueryBy, ",")
-	data.Set("query_by", queryBy)
+	if opts.NumTypos != nil && (*opts.NumTypos < 0 || *opts.NumTypos > 2) {
+		return "", ErrInvalidNumTypos
+	}
+	if len(opts.QueryByWeights) > 0 && len(opts.QueryByWeights) != len(opts.QueryBy) {
+		return "", ErrQueryWeightsMismatch
+	}
+	if len(opts.PrefixByField) > 0 && len(opts.PrefixByField) != len(opts.QueryBy) {
+		return "", ErrPrefixByFieldMismatch
+	}
+	if err := validateInfix(opts.Infix, len(opts.QueryBy)); err != nil {
+		return "", err
+	}
+	if opts.DropTokensMode != "" && !validDropTokensMode(opts.DropTokensMode) {
+		return "", ErrInvalidDropTokensMode
+	}
+	if opts.FacetStrategy != "" && !validFacetStrategy(opts.FacetStrategy) {
+		return "", ErrInvalidFacetStrategy
+	}
+	if opts.TextMatchType != "" && !validTextMatchType(opts.TextMatchType) {
+		return "", ErrInvalidTextMatchType
+	}
 	opts.setOptionalFields(&data)
 	return data.Encode(), nil
 }
 
-func (opts *SearchOptions) setOptionalFields(data *url.Values) {
+func (opts *SearchParameters) setOptionalFields(data *url.Values) {
 	if opts.MaxHits != nil {
 		data.Set("max_hits", strconv.Itoa(*opts.MaxHits))
 	}
-	if opts.Prefix != nil {
+	if len(opts.PrefixByField) > 0 {
+		prefix := make([]string, len(opts.PrefixByField))
+		for i, enabled := range opts.PrefixByField {
+			prefix[i] = strconv.FormatBool(enabled)
+		}
+		data.Set("prefix", strings.Join(prefix, ","))
+	} else if opts.Prefix != nil {
 		data.Set("prefix", strconv.FormatBool(*opts.Prefix))
 	}
+	if len(opts.QueryByWeights) > 0 {
+		weights := make([]string, len(opts.QueryByWeights))
+		for i, weight := range opts.QueryByWeights {
+			weights[i] = strconv.Itoa(weight)
+		}
+		data.Set("query_by_weights", strings.Join(weights, ","))
+	}
 	if opts.FilterBy != nil && len(opts.FilterBy) > 0 {
 		filterBy := strings.Join(opts.FilterBy, " && ")
 		data.Set("filter_by", filterBy)
@@ -190,14 +742,29 @@ func (opts *SearchOptions) setOptionalFields(data *url.Values) {
 		includeFields := strings.Join(opts.IncludeFields, ",")
 		data.Set("include_fields", includeFields)
 	}
-	if opts.ExcludeFields != nil && len(opts.ExcludeFields) > 0 {
-		excludeFields := strings.Join(opts.ExcludeFields, ",")
-		data.Set("exclude_fields", excludeFields)
+	excludeFields := opts.ExcludeFields
+	if opts.ExcludeResponseMetadata {
+		excludeFields = append(excludeFields[:len(excludeFields):len(excludeFields)], "out_of", "search_time_ms")
+	}
+	if len(excludeFields) > 0 {
+		data.Set("exclude_fields", strings.Join(excludeFields, ","))
 	}
 	if opts.HighlightFullFields != nil && len(opts.HighlightFullFields) > 0 {
 		highlightFullFields := strings.Join(opts.HighlightFullFields, ",")
 		data.Set("highlight_full_fields", highlightFullFields)
 	}
+	if len(opts.HighlightFields) > 0 {
+		data.Set("highlight_fields", strings.Join(opts.HighlightFields, ","))
+	}
+	if opts.HighlightStartTag != "" {
+		data.Set("highlight_start_tag", opts.HighlightStartTag)
+	}
+	if opts.HighlightEndTag != "" {
+		data.Set("highlight_end_tag", opts.HighlightEndTag)
+	}
+	if opts.HighlightAffixNumTokens != nil {
+		data.Set("highlight_affix_num_tokens", strconv.Itoa(*opts.HighlightAffixNumTokens))
+	}
 	if opts.SnippetThreshold != nil {
 		data.Set("snippet_threshold", strconv.Itoa(*opts.SnippetThreshold))
 	}
@@ -215,10 +782,165 @@ func (opts *SearchOptions) setOptionalFields(data *url.Values) {
 		hiddenhits := strings.Join(opts.Hiddenhits, ",")
 		data.Set("hidden_hits", hiddenhits)
 	}
+	if opts.VectorQuery != "" {
+		data.Set("vector_query", opts.VectorQuery)
+	}
+	if opts.UseCache {
+		data.Set("use_cache", "true")
+		if opts.CacheTTL > 0 {
+			data.Set("cache_ttl", strconv.Itoa(opts.CacheTTL))
+		}
+	}
+	if opts.DropTokensMode != "" {
+		data.Set("drop_tokens_mode", opts.DropTokensMode)
+	}
+	if opts.AutoHighlightFields && len(opts.IncludeFields) > 0 && len(opts.HighlightFields) == 0 {
+		data.Set("highlight_fields", opts.autoHighlightFields())
+	}
+	if opts.EnableTyposForNumericalTokens != nil {
+		data.Set("enable_typos_for_numerical_tokens", strconv.FormatBool(*opts.EnableTyposForNumericalTokens))
+	}
+	if opts.EnableSynonyms != nil {
+		data.Set("enable_synonyms", strconv.FormatBool(*opts.EnableSynonyms))
+	}
+	if opts.Conversation {
+		data.Set("conversation", "true")
+	}
+	if opts.ConversationModelID != "" {
+		data.Set("conversation_model_id", opts.ConversationModelID)
+	}
+	if opts.ConversationID != "" {
+		data.Set("conversation_id", opts.ConversationID)
+	}
+	if opts.EnableHighlightV1 != nil {
+		data.Set("enable_highlight_v1", strconv.FormatBool(*opts.EnableHighlightV1))
+	}
+	if opts.MaxExtraPrefix != nil {
+		data.Set("max_extra_prefix", strconv.Itoa(*opts.MaxExtraPrefix))
+	}
+	if opts.MaxExtraSuffix != nil {
+		data.Set("max_extra_suffix", strconv.Itoa(*opts.MaxExtraSuffix))
+	}
+	if opts.SplitJoinTokens != "" {
+		data.Set("split_join_tokens", opts.SplitJoinTokens)
+	}
+	if opts.Preset != "" {
+		data.Set("preset", opts.Preset)
+	}
+	if opts.FacetStrategy != "" {
+		data.Set("facet_strategy", opts.FacetStrategy)
+	}
+	if opts.EnableAnalytics != nil {
+		data.Set("enable_analytics", strconv.FormatBool(*opts.EnableAnalytics))
+	}
+	if opts.ExhaustiveSearch != nil {
+		data.Set("exhaustive_search", strconv.FormatBool(*opts.ExhaustiveSearch))
+	}
+	if opts.MinLen1Typo != nil {
+		data.Set("min_len_1typo", strconv.Itoa(*opts.MinLen1Typo))
+	}
+	if opts.MinLen2Typo != nil {
+		data.Set("min_len_2typo", strconv.Itoa(*opts.MinLen2Typo))
+	}
+	if len(opts.Infix) > 0 {
+		data.Set("infix", strings.Join(opts.Infix, ","))
+	}
+	if opts.PrioritizeExactMatch != nil {
+		data.Set("prioritize_exact_match", strconv.FormatBool(*opts.PrioritizeExactMatch))
+	}
+	if opts.PrioritizeTokenPosition != nil {
+		data.Set("prioritize_token_position", strconv.FormatBool(*opts.PrioritizeTokenPosition))
+	}
+	if opts.TextMatchType != "" {
+		data.Set("text_match_type", opts.TextMatchType)
+	}
+	if opts.LimitHits > 0 {
+		data.Set("limit_hits", strconv.Itoa(opts.LimitHits))
+	}
+	if opts.MaxCandidates > 0 {
+		data.Set("max_candidates", strconv.Itoa(opts.MaxCandidates))
+	}
+	if opts.FilterCuratedHits != nil {
+		data.Set("filter_curated_hits", strconv.FormatBool(*opts.FilterCuratedHits))
+	}
+	if opts.EnableOverrides != nil {
+		data.Set("enable_overrides", strconv.FormatBool(*opts.EnableOverrides))
+	}
+	if opts.SynonymPrefix != nil {
+		data.Set("synonym_prefix", strconv.FormatBool(*opts.SynonymPrefix))
+	}
+}
+
+// autoHighlightFields returns the highlight_fields value for the
+// intersection of the QueryBy and IncludeFields fields, or none when
+// they don't overlap.
+func (opts *SearchParameters) autoHighlightFields() string {
+	included := make(map[string]bool, len(opts.IncludeFields))
+	for _, field := range opts.IncludeFields {
+		included[field] = true
+	}
+	var fields []string
+	for _, field := range opts.QueryBy {
+		if included[field] {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return "none"
+	}
+	return strings.Join(fields, ",")
+}
+
+func validDropTokensMode(mode string) bool {
+	if mode == "right_to_left" || mode == "left_to_right" {
+		return true
+	}
+	if !strings.HasPrefix(mode, "both_sides:") {
+		return false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(mode, "both_sides:"))
+	return err == nil && n > 0
+}
+
+// validateInfix checks that the infix modes are valid and that there is
+// one per query by field.
+func validateInfix(infix []string, queryByFields int) error {
+	if len(infix) == 0 {
+		return nil
+	}
+	if len(infix) != queryByFields {
+		return fmt.Errorf("%w: %d modes for %d query by fields", ErrInvalidInfix, len(infix), queryByFields)
+	}
+	for _, mode := range infix {
+		if mode != "off" && mode != "always" && mode != "fallback" {
+			return fmt.Errorf("%w: unknown mode %q", ErrInvalidInfix, mode)
+		}
+	}
+	return nil
+}
+
+func validFacetStrategy(strategy string) bool {
+	switch strategy {
+	case "exhaustive", "top_values", "automatic":
+		return true
+	default:
+		return false
+	}
+}
+
+// validTextMatchType reports whether the text match type is one
+// supported by Typesense.
+func validTextMatchType(matchType string) bool {
+	switch matchType {
+	case "max_score", "max_weight", "sum_score":
+		return true
+	default:
+		return false
+	}
 }
 
 // IndexDocument index a new document in the collection.
-func (c *Client) IndexDocument(collectionName string, document interface{}) *DocumentResponse {
+func (c *Client) IndexDocument(collectionName string, document interface{}, opts ...CallOption) *DocumentResponse {
 	documentResponse := DocumentResponse{}
 	method := http.MethodPost
 	url := fmt.Sprintf(
@@ -229,8 +951,12 @@ func (c *Client) IndexDocument(collectionName string, document interface{}) *Doc
 		collectionsEndpoint,
 		collectionName,
 	)
-	body, _ := json.Marshal(document)
-	resp, err := c.apiCall(method, url, body)
+	body, err := c.marshalNewDocument(document)
+	if err != nil {
+		documentResponse.Error = err
+		return &documentResponse
+	}
+	resp, err := c.apiCall(method, url, body, append(opts, withCompressibleBody())...)
 	if err != nil {
 		documentResponse.Error = err
 		return &documentResponse
@@ -246,19 +972,67 @@ func (c *Client) IndexDocument(collectionName string, document interface{}) *Doc
 		documentResponse.Error = ErrDuplicateID
 		return &documentResponse
 	} else if resp.StatusCode == http.StatusBadRequest {
-		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			apiErr.Message = "status bad request"
-		}
-		documentResponse.Error = apiErr
+		documentResponse.Error = newDocumentError(newAPIError(resp), body)
+		return &documentResponse
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		documentResponse.Error = newAPIError(resp)
 		return &documentResponse
 	}
 	documentResponse.Data, documentResponse.Error = ioutil.ReadAll(resp.Body)
 	return &documentResponse
 }
 
+// CreateDocument creates a new document in the collection, the document
+// can be a struct or a map that marshals into JSON. It returns the
+// created document as returned by Typesense, with its assigned id.
+func (c *Client) CreateDocument(collectionName string, document interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	return c.writeDocument(collectionName, document, opts...)
+}
+
+// UpsertDocument creates the document in the collection or replaces it
+// when a document with the same id already exists. It returns the
+// stored document as returned by Typesense.
+func (c *Client) UpsertDocument(collectionName string, document interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	return c.writeDocument(collectionName, document, append(opts, withQueryParameter("action", "upsert"))...)
+}
+
+func (c *Client) writeDocument(collectionName string, document interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	body, err := c.marshalNewDocument(document)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal document: %w", err)
+	}
+	method := http.MethodPost
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/documents",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+	)
+	resp, err := c.apiCall(method, url, body, append(opts, withCompressibleBody())...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode == http.StatusConflict || resp.StatusCode == http.StatusBadRequest {
+		return nil, newDocumentError(newAPIError(resp), body)
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var createdDocument map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&createdDocument); err != nil {
+		return nil, err
+	}
+	return createdDocument, nil
+}
+
 // RetrieveDocument retrieves a document in the collection by its id.
-func (c *Client) RetrieveDocument(collectionName, documentID string) *DocumentResponse {
+func (c *Client) RetrieveDocument(collectionName, documentID string, opts ...CallOption) *DocumentResponse {
 	documentResponse := DocumentResponse{}
 	method := http.MethodGet
 	url := fmt.Sprintf(
@@ -270,7 +1044,7 @@ func (c *Client) RetrieveDocument(collectionName, documentID string) *DocumentRe
 		collectionName,
 		documentID,
 	)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
 		documentResponse.Error = err
 		return &documentResponse
@@ -282,13 +1056,16 @@ func (c *Client) RetrieveDocument(collectionName, documentID string) *DocumentRe
 	} else if resp.StatusCode == http.StatusUnauthorized {
 		documentResponse.Error = ErrUnauthorized
 		return &documentResponse
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		documentResponse.Error = newAPIError(resp)
+		return &documentResponse
 	}
 	documentResponse.Data, documentResponse.Error = ioutil.ReadAll(resp.Body)
 	return &documentResponse
 }
 
 // DeleteDocument deletes a document in the collection by its id.
-func (c *Client) DeleteDocument(collectionName, documentID string) *DocumentResponse {
+func (c *Client) DeleteDocument(collectionName, documentID string, opts ...CallOption) *DocumentResponse {
 	documentResponse := DocumentResponse{}
 	method := http.MethodDelete
 	url := fmt.Sprintf(
@@ -300,7 +1077,7 @@ func (c *Client) DeleteDocument(collectionName, documentID string) *DocumentResp
 		collectionName,
 		documentID,
 	)
-	resp, err := c.apiCall(method, url, nil)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
 		documentResponse.Error = err
 		return &documentResponse
@@ -312,56 +1089,612 @@ func (c *Client) DeleteDocument(collectionName, documentID string) *DocumentResp
 	} else if resp.StatusCode == http.StatusUnauthorized {
 		documentResponse.Error = ErrUnauthorized
 		return &documentResponse
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		documentResponse.Error = newAPIError(resp)
+		return &documentResponse
 	}
 	documentResponse.Data, documentResponse.Error = ioutil.ReadAll(resp.Body)
 	return &documentResponse
 }
 
-// Search searches for the query using the queryBy argument
-// and other options in searchOptions in the Typesense API.
-func (c *Client) Search(collectionName, query string, queryBy []string, searchOptions *SearchOptions) (*SearchResponse, error) {
-	if searchOptions == nil {
-		searchOptions = &SearchOptions{
-			Query:   query,
-			QueryBy: queryBy,
-		}
+// DeleteDocumentsByQuery deletes all the documents of the collection
+// matching the filterBy expression and returns the number of deleted
+// documents. The documents are deleted in batches of batchSize, when
+// batchSize is zero the Typesense default is used.
+func (c *Client) DeleteDocumentsByQuery(collectionName, filterBy string, batchSize int, opts ...CallOption) (int, error) {
+	if filterBy == "" {
+		return 0, ErrFilterRequired
+	}
+	query := url.Values{}
+	query.Set("filter_by", filterBy)
+	if batchSize > 0 {
+		query.Set("batch_size", strconv.Itoa(batchSize))
 	}
-	urlEncodedForm, err := searchOptions.encodeForm()
+	return c.deleteDocuments(collectionName, query, opts...)
+}
+
+// TruncateDocuments deletes all the documents of the collection and
+// returns the number of deleted documents. The collection itself is
+// kept, with its schema, overrides and synonyms, unlike dropping and
+// creating it again. It uses the truncate parameter of Typesense v27
+// and later. Older servers require a filter and reject the call with a
+// 400 *APIError; with them, use DeleteDocumentsByQuery with a filter
+// that matches all the documents of the collection.
+func (c *Client) TruncateDocuments(collectionName string, opts ...CallOption) (int, error) {
+	return c.deleteDocuments(collectionName, url.Values{"truncate": []string{"true"}}, opts...)
+}
+
+// deleteDocuments deletes the documents of the collection selected by
+// the query and returns the number of deleted documents.
+func (c *Client) deleteDocuments(collectionName string, query url.Values, opts ...CallOption) (int, error) {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/documents?%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		query.Encode(),
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
 	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return 0, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, newAPIError(resp)
+	}
+	type deleteResponse struct {
+		NumDeleted int `json:"num_deleted"`
+	}
+	var deleted deleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&deleted); err != nil {
+		return 0, err
+	}
+	return deleted.NumDeleted, nil
+}
+
+// ConditionalUpdateDocument updates the fields of the document only if
+// its versionField still holds the expectedVersion, it returns
+// ErrVersionConflict otherwise. Typesense doesn't support conditional
+// writes, so the version is compared after fetching the document and
+// before writing it, a concurrent write between both requests is not
+// detected. The partial document should also update the version field.
+func (c *Client) ConditionalUpdateDocument(collectionName, documentID, versionField string, expectedVersion interface{}, partial interface{}, opts ...CallOption) *DocumentResponse {
+	documentResponse := c.RetrieveDocument(collectionName, documentID, opts...)
+	if documentResponse.Error != nil {
+		return documentResponse
+	}
+	var storedDocument map[string]json.RawMessage
+	if err := documentResponse.UnmarshalDocument(&storedDocument); err != nil {
+		return &DocumentResponse{Error: err}
+	}
+	expectedVersionJSON, err := json.Marshal(expectedVersion)
+	if err != nil {
+		return &DocumentResponse{Error: err}
+	}
+	if string(storedDocument[versionField]) != string(expectedVersionJSON) {
+		return &DocumentResponse{Error: ErrVersionConflict}
+	}
+	body, err := c.marshalDocument(partial)
+	if err != nil {
+		return &DocumentResponse{Error: err}
+	}
+	return c.patchDocument(collectionName, documentID, body, opts...)
+}
+
+// UpdateDocument partially updates the document, only the fields of
+// partial are updated. It returns the full updated document. An empty
+// update returns ErrEmptyUpdate without making any request.
+func (c *Client) UpdateDocument(collectionName, documentID string, partial interface{}, opts ...CallOption) (map[string]interface{}, error) {
+	body, err := c.marshalDocument(partial)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal document: %w", err)
+	}
+	if string(body) == "{}" {
+		return nil, ErrEmptyUpdate
+	}
+	documentResponse := c.patchDocument(collectionName, documentID, body, opts...)
+	var updatedDocument map[string]interface{}
+	if err := documentResponse.UnmarshalDocument(&updatedDocument); err != nil {
 		return nil, err
 	}
+	return updatedDocument, nil
+}
+
+func (c *Client) patchDocument(collectionName, documentID string, body []byte, opts ...CallOption) *DocumentResponse {
+	documentResponse := DocumentResponse{}
+	method := http.MethodPatch
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/documents/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		documentID,
+	)
+	resp, err := c.apiCall(method, url, body, opts...)
+	if err != nil {
+		documentResponse.Error = err
+		return &documentResponse
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		documentResponse.Error = ErrDocumentNotFound
+		return &documentResponse
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		documentResponse.Error = ErrUnauthorized
+		return &documentResponse
+	} else if resp.StatusCode == http.StatusBadRequest {
+		documentResponse.Error = newDocumentError(newAPIError(resp), body)
+		return &documentResponse
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		documentResponse.Error = newAPIError(resp)
+		return &documentResponse
+	}
+	documentResponse.Data, documentResponse.Error = ioutil.ReadAll(resp.Body)
+	return &documentResponse
+}
+
+// newDocumentError classifies the error of a rejected document into a
+// *DocumentError with the field its message refers to.
+func newDocumentError(apiErr *APIError, document []byte) *DocumentError {
+	return &DocumentError{
+		Message:  apiErr.Message,
+		Field:    messageField(apiErr.Message),
+		Document: string(document),
+		err:      apiErr,
+	}
+}
 
+// messageField returns the first field quoted in the error message of
+// Typesense, e.g. title for "Field `title` must be a string.", or an
+// empty string when it quotes none.
+func messageField(message string) string {
+	if match := quotedFieldPattern.FindStringSubmatch(message); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// ExportDocuments exports the documents of the collection as a stream
+// of newline delimited JSON, one document per line. The stream is read
+// directly from the response, so the collection is never loaded into
+// memory at once. The caller is responsible for closing the stream. The
+// client timeout also applies while reading the stream, use
+// WithCallTimeout to give big exports a bigger budget. The documents
+// can be filtered with WithFilterBy and their fields picked with
+// WithIncludeFields and WithExcludeFields.
+func (c *Client) ExportDocuments(collectionName string, opts ...CallOption) (io.ReadCloser, error) {
 	method := http.MethodGet
 	url := fmt.Sprintf(
-		"%s://%s:%s/%s/%s/documents/search?%s",
+		"%s://%s:%s/%s/%s/documents/export",
 		c.masterNode.Protocol,
 		c.masterNode.Host,
 		c.masterNode.Port,
 		collectionsEndpoint,
 		collectionName,
-		urlEncodedForm,
 	)
-	req, _ := http.NewRequest(method, url, nil)
-	req.Header.Add(defaultHeaderKey, c.masterNode.APIKey)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.apiCall(method, url, nil, append(opts, withUnlimitedResponse())...)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, ErrNotFound
+		resp.Body.Close()
+		return nil, ErrCollectionNotFound
 	} else if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
 		return nil, ErrUnauthorized
-	} else if resp.StatusCode == http.StatusBadRequest {
-		var apiResponse APIResponse
-		if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-			return nil, err
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// ExportCSV streams all documents of the collection into w as CSV. The
+// first row is a header with the given fields and every document is
+// written as a row with the values of these fields, missing fields are
+// written as empty cells. Array and object values are written as JSON.
+func (c *Client) ExportCSV(collectionName string, fields []string, w io.Writer, opts ...CallOption) error {
+	export, err := c.ExportDocuments(collectionName, opts...)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(fields); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(export)
+	decoder.UseNumber()
+	row := make([]string, len(fields))
+	for {
+		var document map[string]interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		for i, field := range fields {
+			row[i] = csvValue(document[field])
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
 		}
-		return nil, errors.New(apiResponse.Message)
 	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		valueJSON, _ := json.Marshal(v)
+		return string(valueJSON)
+	}
+}
+
+// Search searches the collection using the search parameters in the
+// Typesense API. The Q and QueryBy parameters are required.
+func (c *Client) Search(collectionName string, params SearchParameters, opts ...CallOption) (*SearchResponse, error) {
+	start := time.Now()
+	body, err := c.search(collectionName, params, opts...)
+	if err != nil {
+		if cached, ok := c.cachedResult(collectionName, params, err, opts); ok {
+			return cached, nil
+		}
+		return nil, err
+	}
+	defer body.Close()
 	var searchResponse SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+	if err := json.NewDecoder(body).Decode(&searchResponse); err != nil {
 		return nil, err
 	}
+	searchResponse.RequestDuration = time.Since(start)
+	c.cacheResult(collectionName, params, &searchResponse, opts)
 	return &searchResponse, nil
 }
+
+// TypedSearchResponse is a search response with the documents of the
+// hits decoded into T.
+type TypedSearchResponse[T any] struct {
+	FacetCounts  []FacetCount  `json:"facet_counts"`
+	Found        int           `json:"found"`
+	Hits         []TypedHit[T] `json:"hits"`
+	SearchTimeMs int           `json:"search_time_ms"`
+}
+
+// TypedHit is a search hit with its document decoded into T.
+type TypedHit[T any] struct {
+	Highlights        []SearchHighlight `json:"highlights"`
+	Document          T                 `json:"document"`
+	GeoDistanceMeters map[string]int    `json:"geo_distance_meters,omitempty"`
+	VectorDistance    float64           `json:"vector_distance,omitempty"`
+	TextMatchInfo     *TextMatchInfo    `json:"text_match_info,omitempty"`
+	Curated           bool              `json:"curated,omitempty"`
+}
+
+// SearchTyped searches the collection like Search, decoding the
+// document of each hit into T instead of a map, e.g. into the struct
+// the documents were indexed from.
+func SearchTyped[T any](c *Client, collectionName string, params SearchParameters, opts ...CallOption) (*TypedSearchResponse[T], error) {
+	body, err := c.search(collectionName, params, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var searchResponse TypedSearchResponse[T]
+	if err := json.NewDecoder(body).Decode(&searchResponse); err != nil {
+		return nil, err
+	}
+	return &searchResponse, nil
+}
+
+// Group is a group of hits of a grouped search with their documents
+// decoded into T, the group key holds the values of the GroupBy fields
+// shared by the hits.
+type Group[T any] struct {
+	GroupKey []interface{} `json:"group_key"`
+	Found    int           `json:"found"`
+	Hits     []TypedHit[T] `json:"hits"`
+}
+
+// TypedGroupedSearchResponse is the response of a grouped search with
+// the documents of the hits decoded into T. Found is the number of
+// documents found across all the groups and FoundGroups the number of
+// groups.
+type TypedGroupedSearchResponse[T any] struct {
+	FacetCounts  []FacetCount `json:"facet_counts"`
+	Found        int          `json:"found_docs"`
+	FoundGroups  int          `json:"found"`
+	Groups       []Group[T]   `json:"grouped_hits"`
+	SearchTimeMs int          `json:"search_time_ms"`
+}
+
+// SearchGroupedTyped makes a grouped search like Search, decoding the
+// documents of the hits of each group into T. The GroupBy parameter is
+// required, it returns ErrGroupByRequired otherwise.
+func SearchGroupedTyped[T any](c *Client, collectionName string, params SearchParameters, opts ...CallOption) (*TypedGroupedSearchResponse[T], error) {
+	if len(params.GroupBy) == 0 {
+		return nil, ErrGroupByRequired
+	}
+	body, err := c.search(collectionName, params, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var searchResponse TypedGroupedSearchResponse[T]
+	if err := json.NewDecoder(body).Decode(&searchResponse); err != nil {
+		return nil, err
+	}
+	return &searchResponse, nil
+}
+
+// search makes the search request and returns the body of a successful
+// response, the caller must close it.
+func (c *Client) search(collectionName string, params SearchParameters, opts ...CallOption) (io.ReadCloser, error) {
+	params = c.searchDefaults(params)
+	urlEncodedForm, err := params.encodeForm()
+	if err != nil {
+		return nil, err
+	}
+
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/documents/search?%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		urlEncodedForm,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode == http.StatusBadRequest || resp.StatusCode >= http.StatusInternalServerError {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// searchDefaults returns the parameters with the defaults of the client
+// applied, see WithDefaultQueryBy and WithHighlightV1.
+func (c *Client) searchDefaults(params SearchParameters) SearchParameters {
+	if params.EnableHighlightV1 == nil {
+		params.EnableHighlightV1 = c.highlightV1
+	}
+	if len(params.QueryBy) == 0 {
+		params.QueryBy = c.defaultQueryBy
+	}
+	return params
+}
+
+// ContinueConversation makes a conversational search that follows up
+// the conversation with the given id, the search is always made as
+// a conversational search.
+func (c *Client) ContinueConversation(collectionName, conversationID string, params SearchParameters, opts ...CallOption) (*SearchResponse, error) {
+	params.Conversation = true
+	params.ConversationID = conversationID
+	return c.Search(collectionName, params, opts...)
+}
+
+// Browse lists the documents of the collection without a text query,
+// using only the filters and sorting of the search parameters. The
+// query is always set to the wildcard query "*" and at least one of
+// FilterBy or SortBy is required, so a full scan of the collection is
+// never made by accident.
+func (c *Client) Browse(collectionName string, params SearchParameters, opts ...CallOption) (*SearchResponse, error) {
+	if len(params.FilterBy) == 0 && len(params.SortBy) == 0 {
+		return nil, ErrBrowseFilterRequired
+	}
+	params.Q = wildcardQuery
+	return c.Search(collectionName, params, opts...)
+}
+
+// CountDocuments returns the number of documents of the collection. It
+// makes a wildcard search without hits and returns the number of
+// documents found, which reflects deletions sooner than the
+// NumDocuments of the collection and doesn't fetch the schema.
+func (c *Client) CountDocuments(collectionName string, opts ...CallOption) (int, error) {
+	perPage := 0
+	searchResponse, err := c.Search(collectionName, SearchParameters{Q: wildcardQuery, PerPage: &perPage}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return searchResponse.Found, nil
+}
+
+// PreviewDelete returns the number of documents DeleteDocumentsByQuery
+// would delete with the same filter, without deleting them. Like the
+// deletion, it requires a filter. The count always comes from Typesense,
+// never from the result cache of the client, see WithResultCache.
+func (c *Client) PreviewDelete(collectionName, filterBy string, opts ...CallOption) (int, error) {
+	if filterBy == "" {
+		return 0, ErrFilterRequired
+	}
+	perPage := 0
+	params := SearchParameters{Q: wildcardQuery, FilterBy: []string{filterBy}, PerPage: &perPage}
+	body, err := c.search(collectionName, params, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	var searchResponse struct {
+		Found int `json:"found"`
+	}
+	if err := json.NewDecoder(body).Decode(&searchResponse); err != nil {
+		return 0, err
+	}
+	return searchResponse.Found, nil
+}
+
+// WarmCache runs each of the queries with the server cache enabled so
+// the results are cached before they are needed, e.g. after a deploy
+// and before peak traffic. All queries are run even if some of them
+// fail, the failures are reported together in the returned error.
+func (c *Client) WarmCache(collectionName string, queries []SearchParameters, opts ...CallOption) error {
+	var failures []string
+	for i, query := range queries {
+		query.UseCache = true
+		if _, err := c.Search(collectionName, query, opts...); err != nil {
+			failures = append(failures, fmt.Sprintf("query %d (%q): %v", i, query.Q, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("couldn't warm cache: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// defaultStreamPerPage is the page size used by SearchStream when the
+// search parameters don't set one, it is the maximum allowed by Typesense.
+const defaultStreamPerPage = 250
+
+// SearchStream searches the collection and writes the document of each
+// hit to w as a line of NDJSON, fetching the pages of results until all
+// hits are written. The pages start at the Page of the search
+// parameters and use their PerPage, or the first page and 250 hits per
+// page when they are not set or not positive.
+func (c *Client) SearchStream(collectionName string, params SearchParameters, w io.Writer, opts ...CallOption) error {
+	page := 1
+	if params.Page != nil {
+		page = *params.Page
+	}
+	perPage := defaultStreamPerPage
+	if params.PerPage != nil && *params.PerPage > 0 {
+		perPage = *params.PerPage
+	}
+	params.PerPage = &perPage
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for {
+		currentPage := page
+		params.Page = &currentPage
+		searchResponse, err := c.Search(collectionName, params, opts...)
+		if err != nil {
+			return err
+		}
+		for _, hit := range searchResponse.Hits {
+			if err := encoder.Encode(hit.Document); err != nil {
+				return err
+			}
+		}
+		if len(searchResponse.Hits) == 0 || len(searchResponse.Hits) < perPage || page*perPage >= searchResponse.Found {
+			return nil
+		}
+		page++
+	}
+}
+
+// DocumentIterator iterates over the documents of a collection, paging
+// through the search results, see ListDocuments.
+type DocumentIterator struct {
+	client         *Client
+	collectionName string
+	perPage        int
+	opts           []CallOption
+
+	page     int
+	fetched  int
+	found    int
+	hits     []SearchResultHit
+	current  map[string]interface{}
+	err      error
+	finished bool
+}
+
+// ListDocuments returns an iterator over all documents of the
+// collection, fetching perPage documents in each request. The
+// iteration stops when all documents found were returned or when a
+// request fails, the error is then returned by Err:
+//
+//	documents := client.ListDocuments("companies", 250)
+//	for documents.Next() {
+//		document := documents.Document()
+//	}
+//	if err := documents.Err(); err != nil {
+//		...
+//	}
+func (c *Client) ListDocuments(collectionName string, perPage int, opts ...CallOption) *DocumentIterator {
+	if perPage <= 0 {
+		perPage = defaultStreamPerPage
+	}
+	return &DocumentIterator{
+		client:         c,
+		collectionName: collectionName,
+		perPage:        perPage,
+		opts:           opts,
+	}
+}
+
+// Next advances the iterator to the next document, it returns false
+// when there are no more documents or a request failed.
+func (it *DocumentIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.hits) == 0 {
+		if it.finished {
+			return false
+		}
+		it.fetchNextPage()
+		if it.err != nil || len(it.hits) == 0 {
+			return false
+		}
+	}
+	it.current = it.hits[0].Document
+	it.hits = it.hits[1:]
+	return true
+}
+
+// Document returns the current document of the iterator.
+func (it *DocumentIterator) Document() map[string]interface{} {
+	return it.current
+}
+
+// Err returns the error of the request that stopped the iteration, if
+// any.
+func (it *DocumentIterator) Err() error {
+	return it.err
+}
+
+func (it *DocumentIterator) fetchNextPage() {
+	it.page++
+	page, perPage := it.page, it.perPage
+	params := SearchParameters{Q: wildcardQuery, Page: &page, PerPage: &perPage}
+	searchResponse, err := it.client.Search(it.collectionName, params, it.opts...)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.hits = searchResponse.Hits
+	it.found = searchResponse.Found
+	it.fetched += len(searchResponse.Hits)
+	if len(searchResponse.Hits) < perPage || it.fetched >= it.found {
+		it.finished = true
+	}
+}
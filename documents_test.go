@@ -3,11 +3,16 @@ package typesense
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 type testDocumentStruct struct {
@@ -22,6 +27,7 @@ const (
 		{
 			"facet_counts": [],
 			"found": 62,
+			"search_time_ms": 1,
 			"hits": [
 				{
 					"highlights": [
@@ -61,8 +67,8 @@ var (
 func TestEncodeForm(t *testing.T) {
 	numberValue := 2
 	prefix := true
-	opts := SearchOptions{
-		Query:               "query",
+	opts := SearchParameters{
+		Q:                   "query",
 		QueryBy:             []string{"name"},
 		FilterBy:            []string{"age>3"},
 		SortBy:              []string{"age"},
@@ -134,6 +140,27 @@ func TestRetrieveDocument(t *testing.T) {
 	}
 }
 
+func TestRetrieveDocument_includeAndExcludeFields(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("include_fields") != "title,author" || query.Get("exclude_fields") != "author" {
+			t.Errorf("Expected to receive include_fields %q and exclude_fields %q, received %v", "title,author", "author", query)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"title": "Harry Potter"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documentResp := client.RetrieveDocument("books", "1", WithIncludeFields("title", "author"), WithExcludeFields("author"))
+	if documentResp.Error != nil {
+		t.Errorf("Expected to receive no errors, received %v", documentResp.Error)
+	}
+}
+
 func TestRetrieveDocument_collectionNotFound(t *testing.T) {
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -186,6 +213,101 @@ func TestDeleteDocument_collectionNotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteDocumentsByQuery(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodDelete, req.Method)
+		}
+		query := req.URL.Query()
+		if query.Get("filter_by") != "created_at:<1600000000" {
+			t.Errorf("Expected to receive filter_by %q, received %q", "created_at:<1600000000", query.Get("filter_by"))
+		}
+		if query.Get("batch_size") != "100" {
+			t.Errorf("Expected to receive batch_size %q, received %q", "100", query.Get("batch_size"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"num_deleted": 24}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	numDeleted, err := client.DeleteDocumentsByQuery(collectionNameTest, "created_at:<1600000000", 100)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if numDeleted != 24 {
+		t.Errorf("Expected to delete %d documents, deleted %d", 24, numDeleted)
+	}
+}
+
+func TestDeleteDocumentsByQuery_defaultBatchSize(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Has("batch_size") {
+			t.Errorf("Expected to receive no batch_size, received %q", req.URL.Query().Get("batch_size"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"num_deleted": 0}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.DeleteDocumentsByQuery(collectionNameTest, "created_at:<1600000000", 0); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestTruncateDocuments(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete || req.URL.Path != "/collections/companies/documents" {
+			t.Errorf("Expected to DELETE the documents of the collection, requested %s %s", req.Method, req.URL.Path)
+		}
+		if truncate := req.URL.Query().Get("truncate"); truncate != "true" {
+			t.Errorf("Expected to receive truncate %q, received %q", "true", truncate)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"num_deleted": 1024}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	deleted, err := client.TruncateDocuments("companies")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if deleted != 1024 {
+		t.Errorf("Expected to delete %d documents, deleted %d", 1024, deleted)
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client.httpClient = mockClient
+	if _, err := client.TruncateDocuments("companies"); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestDeleteDocumentsByQuery_filterRequired(t *testing.T) {
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.DeleteDocumentsByQuery(collectionNameTest, "", 0); err != ErrFilterRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrFilterRequired, err)
+	}
+}
+
 func TestSearch(t *testing.T) {
 	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
 		return &http.Response{
@@ -197,13 +319,38 @@ func TestSearch(t *testing.T) {
 		httpClient: mockClient,
 		masterNode: testMasterNode,
 	}
-	searchResp, err := client.Search("books", "harry potter", []string{"title"}, nil)
+	searchResp, err := client.Search("books", SearchParameters{Q: "harry potter", QueryBy: []string{"title"}})
 	if err != nil {
 		t.Errorf("Expected to receive no errors, received %v", err)
 	}
 	if len(searchResp.Hits) == 0 {
 		t.Errorf("Expected to get at least one hit, got %d", len(searchResp.Hits))
 	}
+	if searchResp.SearchTimeMs != 1 {
+		t.Errorf("Expected to receive search time %d, received %d", 1, searchResp.SearchTimeMs)
+	}
+}
+
+func TestSearch_requestDuration(t *testing.T) {
+	latency := 20 * time.Millisecond
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		time.Sleep(latency)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	searchResp, err := client.Search("books", SearchParameters{Q: "harry potter", QueryBy: []string{"title"}})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResp.RequestDuration < latency {
+		t.Errorf("Expected a request duration of at least %v, received %v", latency, searchResp.RequestDuration)
+	}
 }
 
 func TestSearch_notFound(t *testing.T) {
@@ -217,9 +364,9 @@ func TestSearch_notFound(t *testing.T) {
 		httpClient: mockClient,
 		masterNode: testMasterNode,
 	}
-	_, err := client.Search("books", "harry potter", []string{"title"}, nil)
-	if err != ErrNotFound {
-		t.Errorf("Expected to receive error %v, received %v", ErrNotFound, err)
+	_, err := client.Search("books", SearchParameters{Q: "harry potter", QueryBy: []string{"title"}})
+	if err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
 	}
 }
 
@@ -234,7 +381,7 @@ func TestSearch_missingRequiredField(t *testing.T) {
 		httpClient: mockClient,
 		masterNode: testMasterNode,
 	}
-	if _, err := client.Search("books", "harry potter", nil, nil); err != ErrQueryByRequired {
+	if _, err := client.Search("books", SearchParameters{Q: "harry potter"}); err != ErrQueryByRequired {
 		t.Errorf("Expected to receive error %v, received %v", ErrQueryByRequired, err)
 	}
 
@@ -244,8 +391,8 @@ func TestSearch_missingRequiredField(t *testing.T) {
 			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "query_by is required"}`)),
 		}, nil
 	}
-	if _, err := client.Search("books", "", []string{"title"}, nil); err != ErrQueryRequired {
-		t.Errorf("Expected to receive error %v, received %v", ErrQueryRequired, err)
+	if _, err := client.Search("books", SearchParameters{QueryBy: []string{"title"}}); err != ErrSearchQueryRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrSearchQueryRequired, err)
 	}
 }
 
@@ -261,7 +408,1684 @@ func TestSearch_badRequest(t *testing.T) {
 		httpClient: mockClient,
 		masterNode: testMasterNode,
 	}
-	if _, err := client.Search("books", "harry porter", []string{"title"}, nil); err.Error() != errorMessage {
-		t.Errorf("Expected to receive error %q, received %q", errorMessage, err.Error())
+	if _, err := client.Search("books", SearchParameters{Q: "harry porter", QueryBy: []string{"title"}}); !reflect.DeepEqual(err, &APIError{StatusCode: http.StatusBadRequest, Message: errorMessage}) {
+		t.Errorf("Expected to receive error %q, received %v", errorMessage, err)
+	}
+}
+
+func TestRangeFacet(t *testing.T) {
+	ranges := map[string][2]float64{
+		"Premium": {50, 100},
+		"Budget":  {0, 50},
+	}
+	expected := "price(Budget:[0, 50], Premium:[50, 100])"
+	if facetBy := RangeFacet("price", ranges); facetBy != expected {
+		t.Errorf("Expected to receive %v, received %v", expected, facetBy)
+	}
+
+	var facetCount FacetCount
+	facetJSON := `{"field_name": "price", "counts": [{"count": 3, "value": "Premium"}, {"count": 7, "value": "Budget"}]}`
+	if err := json.Unmarshal([]byte(facetJSON), &facetCount); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expectedBuckets := []RangeFacetBucket{
+		{Label: "Budget", Min: 0, Max: 50, Count: 7},
+		{Label: "Premium", Min: 50, Max: 100, Count: 3},
+	}
+	if buckets := facetCount.RangeBuckets(ranges); !reflect.DeepEqual(buckets, expectedBuckets) {
+		t.Errorf("Expected to receive %v, received %v", expectedBuckets, buckets)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	exportBody := `{"id": "1", "name": "Stark Industries", "num_employees": 5215}
+{"id": "2", "name": "Acme, \"Inc\"", "tags": ["a", "b"]}
+`
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "/documents/export") {
+			t.Errorf("Expected to request the export endpoint, requested %v", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(exportBody)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	var buf bytes.Buffer
+	if err := client.ExportCSV(collectionNameTest, []string{"id", "name", "num_employees"}, &buf); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := "id,name,num_employees\n1,Stark Industries,5215\n2,\"Acme, \"\"Inc\"\"\",\n"
+	if buf.String() != expected {
+		t.Errorf("Expected to receive %q, received %q", expected, buf.String())
+	}
+}
+
+func TestExportCSV_collectionNotFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	var buf bytes.Buffer
+	if err := client.ExportCSV(collectionNameTest, []string{"id"}, &buf); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestExportCSV_badFilter(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Could not find a filter field named ` + "`user`" + ` in the schema."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	var buf bytes.Buffer
+	var apiErr *APIError
+	if err := client.ExportCSV(collectionNameTest, []string{"id"}, &buf, WithFilterBy("user:=42")); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected to receive an *APIError with status %d, received %v", http.StatusBadRequest, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected to write nothing, wrote %q", buf.String())
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("q") != "*" {
+			t.Errorf("Expected to search with the wildcard query, searched with %v", query.Get("q"))
+		}
+		if query.Get("filter_by") != "country:US" {
+			t.Errorf("Expected to filter by %v, filtered by %v", "country:US", query.Get("filter_by"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	searchResponse, err := client.Browse(collectionNameTest, SearchParameters{FilterBy: []string{"country:US"}})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if searchResponse == nil || searchResponse.Found != 62 {
+		t.Errorf("Expected to receive the search response, received %v", searchResponse)
+	}
+}
+
+func TestBrowse_filterRequired(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not make any request")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.Browse(collectionNameTest, SearchParameters{}); err != ErrBrowseFilterRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrBrowseFilterRequired, err)
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	var queries []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("use_cache") != "true" {
+			t.Errorf("Expected to search with the cache enabled, searched with use_cache=%v", query.Get("use_cache"))
+		}
+		queries = append(queries, query.Get("q"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	err := client.WarmCache(collectionNameTest, []SearchParameters{
+		{Q: "harry", QueryBy: []string{"title"}},
+		{Q: "potter", QueryBy: []string{"title"}},
+	})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"harry", "potter"}; !reflect.DeepEqual(queries, expected) {
+		t.Errorf("Expected to search for %v, searched for %v", expected, queries)
+	}
+}
+
+func TestSearchResponse_foundGroups(t *testing.T) {
+	var searchResponse SearchResponse
+	groupedJSON := `{
+		"facet_counts": [],
+		"found": 2,
+		"found_docs": 5,
+		"grouped_hits": [
+			{"group_key": ["Stark"], "found": 3, "hits": [{"document": {"id": "1"}}, {"document": {"id": "2"}}]},
+			{"group_key": ["Acme"], "found": 2, "hits": [{"document": {"id": "3"}}]}
+		]
+	}`
+	if err := json.Unmarshal([]byte(groupedJSON), &searchResponse); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if searchResponse.FoundGroups != 2 {
+		t.Errorf("Expected to receive %v groups, received %v", 2, searchResponse.FoundGroups)
+	}
+	if searchResponse.Found != 5 {
+		t.Errorf("Expected to receive %v documents, received %v", 5, searchResponse.Found)
+	}
+	if len(searchResponse.Hits) != 0 || len(searchResponse.GroupedHits) != 2 {
+		t.Fatalf("Expected to receive the hits in %v groups, received %v", 2, searchResponse.GroupedHits)
+	}
+	if group := searchResponse.GroupedHits[0]; group.GroupKey[0] != "Stark" || group.Found != 3 || len(group.Hits) != 2 {
+		t.Errorf("Expected to receive the Stark group with 2 hits, received %+v", group)
+	}
+
+	var ungroupedResponse SearchResponse
+	if err := json.Unmarshal([]byte(searchResultTest), &ungroupedResponse); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if ungroupedResponse.Found != 62 || ungroupedResponse.FoundGroups != 0 {
+		t.Errorf("Expected to receive 62 documents and no groups, received %v and %v", ungroupedResponse.Found, ungroupedResponse.FoundGroups)
+	}
+}
+
+func TestConditionalUpdateDocument(t *testing.T) {
+	patched := false
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPatch {
+			patched = true
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "name": "Stark Industries", "version": 4}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "name": "Stark", "version": 3}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	partial := map[string]interface{}{"name": "Stark Industries", "version": 4}
+	documentResp := client.ConditionalUpdateDocument(collectionNameTest, "1", "version", 3, partial)
+	if documentResp.Error != nil {
+		t.Errorf("Expected to receive no errors, received %v", documentResp.Error)
+	}
+	if !patched {
+		t.Errorf("Expected the document to be updated")
+	}
+}
+
+func TestConditionalUpdateDocument_versionConflict(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPatch {
+			t.Errorf("Expected the document to not be updated")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "name": "Stark", "version": 5}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	partial := map[string]interface{}{"name": "Stark Industries", "version": 4}
+	documentResp := client.ConditionalUpdateDocument(collectionNameTest, "1", "version", 3, partial)
+	if documentResp.Error != ErrVersionConflict {
+		t.Errorf("Expected to receive error %v, received %v", ErrVersionConflict, documentResp.Error)
+	}
+}
+
+func TestCreateDocument(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/collections/test/documents" {
+			t.Errorf("Expected to POST to /collections/test/documents, requested %v %v", req.Method, req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "0", "field1": "test", "field2": 10}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	document, err := client.CreateDocument(collectionNameTest, testDocument)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if document["id"] != "0" {
+		t.Errorf("Expected to receive the document with id %v, received %v", "0", document)
+	}
+}
+
+func TestCreateDocument_errors(t *testing.T) {
+	documentJSON, _ := json.Marshal(testDocument)
+	tests := []struct {
+		statusCode int
+		body       string
+		expected   error
+	}{
+		{http.StatusNotFound, `{"message": "Not Found"}`, ErrCollectionNotFound},
+		{http.StatusUnauthorized, `{"message": "Forbidden"}`, ErrUnauthorized},
+		{http.StatusConflict, `{"message": "A document with id 0 already exists."}`, newDocumentError(&APIError{StatusCode: http.StatusConflict, Message: "A document with id 0 already exists."}, documentJSON)},
+		{http.StatusBadRequest, `{"message": "Field ` + "`field2`" + ` must be an int32."}`, newDocumentError(&APIError{StatusCode: http.StatusBadRequest, Message: "Field `field2` must be an int32."}, documentJSON)},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: test.statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(test.body)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		if _, err := client.CreateDocument(collectionNameTest, testDocument); !reflect.DeepEqual(err, test.expected) {
+			t.Errorf("Expected to receive error %v, received %v", test.expected, err)
+		}
+	}
+}
+
+func TestCreateDocument_marshalError(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not make any request")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	_, err := client.CreateDocument(collectionNameTest, map[string]interface{}{"invalid": make(chan int)})
+	var unsupportedTypeErr *json.UnsupportedTypeError
+	if !errors.As(err, &unsupportedTypeErr) {
+		t.Errorf("Expected to receive a wrapped marshal error, received %v", err)
+	}
+}
+
+func TestEncodeForm_dropTokensMode(t *testing.T) {
+	for _, mode := range []string{"right_to_left", "left_to_right", "both_sides:3"} {
+		opts := SearchParameters{Q: "query", QueryBy: []string{"name"}, DropTokensMode: mode}
+		form, err := opts.encodeForm()
+		if err != nil {
+			t.Errorf("Expected to receive no errors for %v, received %v", mode, err)
+		}
+		values, _ := url.ParseQuery(form)
+		if values.Get("drop_tokens_mode") != mode {
+			t.Errorf("Expected drop_tokens_mode %v, received %v", mode, values.Get("drop_tokens_mode"))
+		}
+	}
+	for _, mode := range []string{"middle_out", "both_sides:", "both_sides:x"} {
+		opts := SearchParameters{Q: "query", QueryBy: []string{"name"}, DropTokensMode: mode}
+		if _, err := opts.encodeForm(); err != ErrInvalidDropTokensMode {
+			t.Errorf("Expected to receive error %v for %v, received %v", ErrInvalidDropTokensMode, mode, err)
+		}
+	}
+}
+
+func TestUpdateDocument(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPatch || req.URL.Path != "/collections/test/documents/1" {
+			t.Errorf("Expected to PATCH /collections/test/documents/1, requested %v %v", req.Method, req.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != `{"field2":11}` {
+			t.Errorf("Expected to send only the updated fields, sent %s", body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "field1": "test", "field2": 11}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+		idField:    "sku",
+	}
+	document, err := client.UpdateDocument(collectionNameTest, "1", map[string]interface{}{"field2": 11})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := map[string]interface{}{"id": "1", "field1": "test", "field2": float64(11)}
+	if !reflect.DeepEqual(document, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, document)
+	}
+}
+
+func TestUpdateDocument_errors(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+		expected   error
+	}{
+		{http.StatusNotFound, `{"message": "Could not find a document with id: 1"}`, ErrDocumentNotFound},
+		{http.StatusBadRequest, `{"message": "Field ` + "`field2`" + ` must be an int32."}`, newDocumentError(&APIError{StatusCode: http.StatusBadRequest, Message: "Field `field2` must be an int32."}, []byte(`{"field2":"eleven"}`))},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: test.statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(test.body)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		if _, err := client.UpdateDocument(collectionNameTest, "1", map[string]interface{}{"field2": "eleven"}); !reflect.DeepEqual(err, test.expected) {
+			t.Errorf("Expected to receive error %v, received %v", test.expected, err)
+		}
+	}
+}
+
+func TestUpdateDocument_emptyUpdate(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not make any request")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.UpdateDocument(collectionNameTest, "1", map[string]interface{}{}); err != ErrEmptyUpdate {
+		t.Errorf("Expected to receive error %v, received %v", ErrEmptyUpdate, err)
+	}
+}
+
+func TestEncodeForm_autoHighlightFields(t *testing.T) {
+	tests := []struct {
+		queryBy       []string
+		includeFields []string
+		expected      string
+	}{
+		{[]string{"title", "description", "authors"}, []string{"id", "authors", "title"}, "title,authors"},
+		{[]string{"description"}, []string{"id", "title"}, "none"},
+		{[]string{"title"}, nil, ""},
+	}
+	for _, test := range tests {
+		opts := SearchParameters{
+			Q:                   "query",
+			QueryBy:             test.queryBy,
+			IncludeFields:       test.includeFields,
+			AutoHighlightFields: true,
+		}
+		form, err := opts.encodeForm()
+		if err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		values, _ := url.ParseQuery(form)
+		if highlightFields := values.Get("highlight_fields"); highlightFields != test.expected {
+			t.Errorf("Expected highlight_fields %q, received %q", test.expected, highlightFields)
+		}
+	}
+}
+
+func TestEncodeForm_enableTyposForNumericalTokens(t *testing.T) {
+	enableTypos := false
+	opts := SearchParameters{Q: "12345", QueryBy: []string{"sku"}, EnableTyposForNumericalTokens: &enableTypos}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if value := values.Get("enable_typos_for_numerical_tokens"); value != "false" {
+		t.Errorf("Expected enable_typos_for_numerical_tokens %v, received %v", "false", value)
+	}
+
+	opts.EnableTyposForNumericalTokens = nil
+	form, _ = opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("enable_typos_for_numerical_tokens") {
+		t.Errorf("Expected enable_typos_for_numerical_tokens to not be set")
+	}
+}
+
+func TestEncodeForm_enableSynonyms(t *testing.T) {
+	opts := SearchParameters{Q: "query", QueryBy: []string{"name"}}
+	form, _ := opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("enable_synonyms") {
+		t.Errorf("Expected enable_synonyms to not be set by default")
+	}
+
+	enableSynonyms := false
+	opts.EnableSynonyms = &enableSynonyms
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if value := values.Get("enable_synonyms"); value != "false" {
+		t.Errorf("Expected enable_synonyms %v, received %v", "false", value)
+	}
+}
+
+func TestExportDocuments(t *testing.T) {
+	exportBody := `{"id": "1", "name": "Stark Industries"}
+{"id": "2", "name": "Acme"}
+`
+	closed := false
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: &testReadCloser{
+				Reader:  strings.NewReader(exportBody),
+				onClose: func() { closed = true },
+			},
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	export, err := client.ExportDocuments(collectionNameTest)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if closed {
+		t.Errorf("Expected the export stream to be open")
+	}
+	data, _ := ioutil.ReadAll(export)
+	if string(data) != exportBody {
+		t.Errorf("Expected to receive %q, received %q", exportBody, string(data))
+	}
+	export.Close()
+	if !closed {
+		t.Errorf("Expected closing the export stream to close the response body")
+	}
+}
+
+func TestExportDocuments_withParameters(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		expected := map[string]string{
+			"filter_by":      "user_id:=42",
+			"include_fields": "id,email",
+			"exclude_fields": "password",
+		}
+		for name, value := range expected {
+			if received := req.URL.Query().Get(name); received != value {
+				t.Errorf("Expected %s %q, received %q", name, value, received)
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1", "email": "tony@stark.com"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	export, err := client.ExportDocuments(
+		collectionNameTest,
+		WithFilterBy("user_id:=42"),
+		WithIncludeFields("id", "email"),
+		WithExcludeFields("password"),
+	)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	export.Close()
+}
+
+func TestExportDocuments_collectionNotFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.ExportDocuments(collectionNameTest); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestExportDocuments_errorResponse(t *testing.T) {
+	errorMessage := "Could not find a filter field named `user` in the schema."
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "` + errorMessage + `"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	expected := &APIError{StatusCode: http.StatusBadRequest, Message: errorMessage}
+	if _, err := client.ExportDocuments(collectionNameTest, WithFilterBy("user:=42")); !reflect.DeepEqual(err, expected) {
+		t.Errorf("Expected to receive error %v, received %v", expected, err)
+	}
+}
+
+type testReadCloser struct {
+	io.Reader
+	onClose func()
+}
+
+func (rc *testReadCloser) Close() error {
+	rc.onClose()
+	return nil
+}
+
+func TestSearchStream(t *testing.T) {
+	var requestedPages []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requestedPages = append(requestedPages, req.URL.Query().Get("page"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 2, "hits": [{"document": {"id": "1", "title": "Harry Potter"}}, {"document": {"id": "2", "title": "The Hobbit"}}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	var output bytes.Buffer
+	if err := client.SearchStream("books", SearchParameters{Q: "*"}, &output); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected to write %d lines, wrote %d: %q", 2, len(lines), output.String())
+	}
+	expectedLine := `{"id":"1","title":"Harry Potter"}`
+	if lines[0] != expectedLine {
+		t.Errorf("Expected to write line %s, wrote %s", expectedLine, lines[0])
+	}
+	if len(requestedPages) != 1 || requestedPages[0] != "1" {
+		t.Errorf("Expected to request only the first page, requested %v", requestedPages)
+	}
+}
+
+func TestSearchStream_paging(t *testing.T) {
+	var requestedPages []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+		requestedPages = append(requestedPages, page)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 2, "hits": [{"document": {"id": "` + page + `"}}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	perPage := 1
+	var output bytes.Buffer
+	if err := client.SearchStream("books", SearchParameters{Q: "*", PerPage: &perPage}, &output); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if strings.Join(requestedPages, ",") != "1,2" {
+		t.Errorf("Expected to request pages 1,2, requested %v", requestedPages)
+	}
+	if output.String() != "{\"id\":\"1\"}\n{\"id\":\"2\"}\n" {
+		t.Errorf("Expected to write both pages, wrote %q", output.String())
+	}
+}
+
+func TestSearchStream_nonPositivePerPage(t *testing.T) {
+	var requests []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL.Query().Get("per_page"))
+		if len(requests) > 3 {
+			t.Fatalf("Expected the stream to stop, made %d requests", len(requests))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"found": 10, "hits": []}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	perPage := 0
+	var output bytes.Buffer
+	if err := client.SearchStream("books", SearchParameters{Q: "*", PerPage: &perPage}, &output); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"250"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to request a single page of 250 hits, requested per_page %v", requests)
+	}
+}
+
+func TestSearch_conversation(t *testing.T) {
+	var conversationIDs []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("conversation") != "true" {
+			t.Errorf("Expected to receive conversation %q, received %q", "true", query.Get("conversation"))
+		}
+		conversationIDs = append(conversationIDs, query.Get("conversation_id"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 0, "hits": [], "conversation": {"answer": "Harry Potter is a wizard.", "conversation_id": "123"}}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{
+		Q:                   "who is harry potter?",
+		QueryBy:             []string{"embedding"},
+		Conversation:        true,
+		ConversationModelID: "conv-model-1",
+	}
+	searchResp, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResp.Conversation == nil || searchResp.Conversation.ConversationID != "123" {
+		t.Fatalf("Expected to receive conversation %q, received %+v", "123", searchResp.Conversation)
+	}
+	if searchResp.Conversation.Answer != "Harry Potter is a wizard." {
+		t.Errorf("Expected to receive the answer of the conversation, received %q", searchResp.Conversation.Answer)
+	}
+	params.Q = "who are his friends?"
+	if _, err := client.ContinueConversation("books", searchResp.Conversation.ConversationID, params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"", "123"}; !reflect.DeepEqual(conversationIDs, expected) {
+		t.Errorf("Expected to receive conversation ids %v, received %v", expected, conversationIDs)
+	}
+}
+
+func TestSearch_facetQuery(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("facet_query") != "category:shoe" {
+			t.Errorf("Expected to receive facet_query %q, received %q", "category:shoe", req.URL.Query().Get("facet_query"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"found": 3,
+				"hits": [],
+				"facet_counts": [{"field_name": "category", "counts": [
+					{"count": 2, "value": "shoes", "highlighted": "<mark>shoe</mark>s"},
+					{"count": 1, "value": "shoe polish", "highlighted": "<mark>shoe</mark> polish"}
+				]}]
+			}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	facetQuery := "category:shoe"
+	params := SearchParameters{Q: "*", FacetBy: []string{"category"}, FacetQuery: &facetQuery}
+	searchResp, err := client.Search("products", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(searchResp.FacetCounts) != 1 || len(searchResp.FacetCounts[0].Counts) != 2 {
+		t.Fatalf("Expected to receive two matched facet values, received %+v", searchResp.FacetCounts)
+	}
+	if highlighted := searchResp.FacetCounts[0].Counts[0].Highlighted; highlighted != "<mark>shoe</mark>s" {
+		t.Errorf("Expected to receive highlighted value %q, received %q", "<mark>shoe</mark>s", highlighted)
+	}
+}
+
+func TestSearch_highlightV1Default(t *testing.T) {
+	var received []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		received = append(received, req.URL.Query().Get("enable_highlight_v1"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	highlightV1 := false
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		highlightV1: &highlightV1,
+	}
+	params := SearchParameters{Q: "harry potter", QueryBy: []string{"title"}}
+	if _, err := client.Search("books", params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	enabled := true
+	params.EnableHighlightV1 = &enabled
+	if _, err := client.Search("books", params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"false", "true"}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected to receive enable_highlight_v1 %v, received %v", expected, received)
+	}
+}
+
+func TestEncodeForm_maxExtraPrefixAndSuffix(t *testing.T) {
+	maxExtraPrefix, maxExtraSuffix := 0, 4
+	opts := SearchParameters{Q: "phone", QueryBy: []string{"name"}, MaxExtraPrefix: &maxExtraPrefix, MaxExtraSuffix: &maxExtraSuffix}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if values.Get("max_extra_prefix") != "0" || values.Get("max_extra_suffix") != "4" {
+		t.Errorf("Expected max_extra_prefix 0 and max_extra_suffix 4, received %q and %q", values.Get("max_extra_prefix"), values.Get("max_extra_suffix"))
+	}
+	opts = SearchParameters{Q: "phone", QueryBy: []string{"name"}}
+	form, _ = opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("max_extra_prefix") || values.Has("max_extra_suffix") {
+		t.Errorf("Expected to not send max_extra_prefix and max_extra_suffix when they are not set, received %s", form)
+	}
+}
+
+func TestSearchTyped(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"found": 1,
+				"hits": [{"document": {"id": "124", "company_name": "Stark Industries", "num_employees": 5215, "country": "USA"}}]
+			}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	searchResp, err := SearchTyped[testCompany](&client, "companies", SearchParameters{Q: "stark", QueryBy: []string{"company_name"}})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResp.Found != 1 || len(searchResp.Hits) != 1 {
+		t.Fatalf("Expected to receive one hit, received %+v", searchResp)
+	}
+	if company := searchResp.Hits[0].Document; company.CompanyName != "Stark Industries" || company.NumEmployees != 5215 {
+		t.Errorf("Expected to decode the document into a company, received %+v", company)
+	}
+}
+
+func TestSearchGroupedTyped(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if groupBy := req.URL.Query().Get("group_by"); groupBy != "country" {
+			t.Errorf("Expected to group by %q, received %q", "country", groupBy)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"found": 2,
+				"found_docs": 3,
+				"grouped_hits": [
+					{"group_key": ["USA"], "found": 2, "hits": [
+						{"document": {"id": "124", "company_name": "Stark Industries", "num_employees": 5215, "country": "USA"}},
+						{"document": {"id": "125", "company_name": "Acme", "num_employees": 10, "country": "USA"}}
+					]},
+					{"group_key": ["UK"], "found": 1, "hits": [
+						{"document": {"id": "126", "company_name": "Globex", "num_employees": 300, "country": "UK"}}
+					]}
+				]
+			}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{Q: "*", QueryBy: []string{"company_name"}, GroupBy: []string{"country"}}
+	searchResp, err := SearchGroupedTyped[testCompany](&client, "companies", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResp.Found != 3 || searchResp.FoundGroups != 2 || len(searchResp.Groups) != 2 {
+		t.Fatalf("Expected to receive 3 documents in 2 groups, received %+v", searchResp)
+	}
+	group := searchResp.Groups[0]
+	if !reflect.DeepEqual(group.GroupKey, []interface{}{"USA"}) || group.Found != 2 || len(group.Hits) != 2 {
+		t.Errorf("Expected to receive the USA group with 2 hits, received %+v", group)
+	}
+	if company := searchResp.Groups[1].Hits[0].Document; company.CompanyName != "Globex" || company.NumEmployees != 300 {
+		t.Errorf("Expected to decode the document into a company, received %+v", company)
+	}
+	params.GroupBy = nil
+	if _, err := SearchGroupedTyped[testCompany](&client, "companies", params); err != ErrGroupByRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrGroupByRequired, err)
+	}
+}
+
+func TestSearchTyped_notFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not found."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := SearchTyped[testCompany](&client, "companies", SearchParameters{Q: "stark", QueryBy: []string{"company_name"}}); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestRecallProfile(t *testing.T) {
+	tests := []struct {
+		profile             string
+		numTypos            string
+		typoTokensThreshold string
+		dropTokensThreshold string
+		splitJoinTokens     string
+	}{
+		{"strict", "0", "0", "0", "off"},
+		{"balanced", "2", "1", "1", "fallback"},
+		{"fuzzy", "2", "100", "100", "always"},
+	}
+	for _, test := range tests {
+		opts := RecallProfile(test.profile)
+		opts.Q = "query"
+		opts.QueryBy = []string{"name"}
+		form, err := opts.encodeForm()
+		if err != nil {
+			t.Errorf("Expected to receive no errors, received %v", err)
+		}
+		values, _ := url.ParseQuery(form)
+		received := []string{values.Get("num_typos"), values.Get("typo_tokens_threshold"), values.Get("drop_tokens_threshold"), values.Get("split_join_tokens")}
+		expected := []string{test.numTypos, test.typoTokensThreshold, test.dropTokensThreshold, test.splitJoinTokens}
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("Expected the %s profile to set %v, received %v", test.profile, expected, received)
+		}
+	}
+	if opts := RecallProfile("unknown"); !reflect.DeepEqual(opts, SearchParameters{}) {
+		t.Errorf("Expected an unknown profile to set no parameters, received %+v", opts)
+	}
+}
+
+func TestSearchResponse_facetCounts(t *testing.T) {
+	facetedJSON := `{
+		"found": 3,
+		"hits": [],
+		"facet_counts": [
+			{"field_name": "country", "counts": [{"count": 2, "value": "USA", "highlighted": "USA"}, {"count": 1, "value": "UK", "highlighted": "UK"}], "stats": {"total_values": 2}},
+			{"field_name": "num_employees", "counts": [{"count": 1, "value": "5215", "highlighted": "5215"}], "stats": {"min": 120, "max": 5215, "avg": 1845.5, "sum": 5536.5, "total_values": 3}}
+		]
+	}`
+	var searchResponse SearchResponse
+	if err := json.Unmarshal([]byte(facetedJSON), &searchResponse); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(searchResponse.FacetCounts) != 2 {
+		t.Fatalf("Expected to receive %d facet counts, received %d", 2, len(searchResponse.FacetCounts))
+	}
+	country := searchResponse.FacetCounts[0]
+	expectedValues := []FacetValue{{Count: 2, Value: "USA", Highlighted: "USA"}, {Count: 1, Value: "UK", Highlighted: "UK"}}
+	if country.FieldName != "country" || !reflect.DeepEqual(country.Counts, expectedValues) {
+		t.Errorf("Expected to receive the country facet values %v, received %v", expectedValues, country.Counts)
+	}
+	expectedStats := FacetStats{Min: 120, Max: 5215, Avg: 1845.5, Sum: 5536.5, TotalValues: 3}
+	if stats := searchResponse.FacetCounts[1].Stats; stats != expectedStats {
+		t.Errorf("Expected to receive the num_employees stats %+v, received %+v", expectedStats, stats)
+	}
+}
+
+func TestListDocuments(t *testing.T) {
+	var requestedPages []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+		requestedPages = append(requestedPages, page)
+		body := `{"found": 3, "hits": [{"document": {"id": "1"}}, {"document": {"id": "2"}}]}`
+		if page == "2" {
+			body = `{"found": 3, "hits": [{"document": {"id": "3"}}]}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documents := client.ListDocuments("companies", 2)
+	var ids []interface{}
+	for documents.Next() {
+		ids = append(ids, documents.Document()["id"])
+	}
+	if err := documents.Err(); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []interface{}{"1", "2", "3"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected to list documents %v, listed %v", expected, ids)
+	}
+	if expected := []string{"1", "2"}; !reflect.DeepEqual(requestedPages, expected) {
+		t.Errorf("Expected to request pages %v, requested %v", expected, requestedPages)
+	}
+}
+
+func TestListDocuments_error(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("page") == "2" {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"found": 4, "hits": [{"document": {"id": "1"}}, {"document": {"id": "2"}}]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documents := client.ListDocuments("companies", 2)
+	listed := 0
+	for documents.Next() {
+		listed++
+	}
+	if listed != 2 {
+		t.Errorf("Expected to list %d documents before the error, listed %d", 2, listed)
+	}
+	if err := documents.Err(); err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+}
+
+func TestSearch_geoDistance(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 2, "hits": [
+					{"document": {"id": "1"}, "highlights": [], "geo_distance_meters": {"location": 2300}},
+					{"document": {"id": "2"}, "highlights": []}
+				]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{
+		Q:        "*",
+		QueryBy:  []string{"name"},
+		FilterBy: []string{"location:(48.853, 2.344, 5 km)"},
+	}
+	searchResp, err := client.Search("places", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if expected := map[string]int{"location": 2300}; !reflect.DeepEqual(searchResp.Hits[0].GeoDistanceMeters, expected) {
+		t.Errorf("Expected to receive geo distance %v, received %v", expected, searchResp.Hits[0].GeoDistanceMeters)
+	}
+	if searchResp.Hits[1].GeoDistanceMeters != nil {
+		t.Errorf("Expected to receive no geo distance, received %v", searchResp.Hits[1].GeoDistanceMeters)
+	}
+}
+
+func TestUpsertDocument(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/collections/test/documents" {
+			t.Errorf("Expected to POST to /collections/test/documents, requested %v %v", req.Method, req.URL.Path)
+		}
+		if action := req.URL.Query().Get("action"); action != "upsert" {
+			t.Errorf("Expected to receive action %q, received %q", "upsert", action)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "0", "field1": "test", "field2": 10}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	document, err := client.UpsertDocument(collectionNameTest, testDocument)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if document["id"] != "0" {
+		t.Errorf("Expected to receive the document with id %v, received %v", "0", document)
+	}
+}
+
+func TestUpsertDocument_badRequest(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Field ` + "`field2`" + ` must be an int32."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	_, err := client.UpsertDocument(collectionNameTest, testDocument)
+	var documentErr *DocumentError
+	if !errors.As(err, &documentErr) {
+		t.Fatalf("Expected to receive a *DocumentError, received %v", err)
+	}
+	documentJSON, _ := json.Marshal(testDocument)
+	if documentErr.Field != "field2" || documentErr.Document != string(documentJSON) {
+		t.Errorf("Expected the error of the field field2 of %s, received %+v", documentJSON, documentErr)
+	}
+	expected := &APIError{StatusCode: http.StatusBadRequest, Message: "Field `field2` must be an int32."}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !reflect.DeepEqual(apiErr, expected) {
+		t.Errorf("Expected to wrap error %v, received %v", expected, err)
+	}
+}
+
+func TestSearch_defaultQueryBy(t *testing.T) {
+	var received []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		received = append(received, req.URL.Query().Get("query_by"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{Q: "harry potter"}
+	if _, err := client.Search("books", params); err != ErrQueryByRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrQueryByRequired, err)
+	}
+	WithDefaultQueryBy("title")(&client)
+	if _, err := client.Search("books", params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	params.QueryBy = []string{"author"}
+	if _, err := client.Search("books", params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"title", "author"}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected to receive query_by %v, received %v", expected, received)
+	}
+}
+
+func TestSearch_excludeResponseMetadata(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if excludeFields := req.URL.Query().Get("exclude_fields"); excludeFields != "description,out_of,search_time_ms" {
+			t.Errorf("Expected to receive exclude_fields %q, received %q", "description,out_of,search_time_ms", excludeFields)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 1, "page": 1, "request_params": {"collection_name": "books", "per_page": 10, "q": "harry"},
+				"search_cutoff": false, "hits": [{"document": {"id": "1", "title": "Harry Potter"}, "highlights": []}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{
+		Q:                       "harry",
+		QueryBy:                 []string{"title"},
+		ExcludeFields:           []string{"description"},
+		ExcludeResponseMetadata: true,
+	}
+	searchResp, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResp.Found != 1 || len(searchResp.Hits) != 1 || searchResp.Hits[0].Document["title"] != "Harry Potter" {
+		t.Errorf("Expected to receive the hit of the search, received %+v", searchResp)
+	}
+}
+
+func TestPhraseQuery(t *testing.T) {
+	if phrase := PhraseQuery(`the "deathly" hallows`); phrase != `"the deathly hallows"` {
+		t.Errorf("Expected to receive %q, received %q", `"the deathly hallows"`, phrase)
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if q := req.URL.Query().Get("q"); q != `harry "deathly hallows"` {
+			t.Errorf("Expected to receive q %q, received %q", `harry "deathly hallows"`, q)
+		}
+		if !strings.Contains(req.URL.RawQuery, "q=harry+%22deathly+hallows%22") {
+			t.Errorf("Expected the quotes to be encoded once, received %s", req.URL.RawQuery)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	params := SearchParameters{Q: "harry " + PhraseQuery("deathly hallows"), QueryBy: []string{"title"}}
+	if _, err := client.Search("books", params); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestEncodeForm_facetStrategy(t *testing.T) {
+	opts := SearchParameters{Q: "shoes", QueryBy: []string{"name"}, FacetBy: []string{"brand"}, FacetStrategy: "top_values"}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if values, _ := url.ParseQuery(form); values.Get("facet_strategy") != "top_values" {
+		t.Errorf("Expected facet_strategy %v, received %v", "top_values", values.Get("facet_strategy"))
+	}
+	opts.FacetStrategy = "fastest"
+	if _, err := opts.encodeForm(); err != ErrInvalidFacetStrategy {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidFacetStrategy, err)
+	}
+}
+
+func TestCountDocuments(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("q") != "*" || query.Get("per_page") != "0" {
+			t.Errorf("Expected to search %q with per_page 0, received %v", "*", query)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"found": 1250, "hits": []}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	count, err := client.CountDocuments("books")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if count != 1250 {
+		t.Errorf("Expected to receive count %d, received %d", 1250, count)
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not found."}`)),
+		}, nil
+	}
+	client.httpClient = mockClient
+	if _, err := client.CountDocuments("books"); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestPreviewDelete(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			t.Errorf("Expected to only search, requested %v %v", req.Method, req.URL.Path)
+		}
+		query := req.URL.Query()
+		if query.Get("filter_by") != "num_employees:<100" || query.Get("per_page") != "0" {
+			t.Errorf("Expected to search filtering by %q with per_page 0, received %v", "num_employees:<100", query)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"found": 42, "hits": []}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	count, err := client.PreviewDelete("companies", "num_employees:<100")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected to receive count %d, received %d", 42, count)
+	}
+	if _, err := client.PreviewDelete("companies", ""); err != ErrFilterRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrFilterRequired, err)
+	}
+}
+
+func TestPreviewDelete_resultCache(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: errors.New("connection refused")}
+	}
+	perPage := 0
+	params := SearchParameters{Q: wildcardQuery, FilterBy: []string{"num_employees:<100"}, PerPage: &perPage}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+		resultCache: &testResultCache{results: map[string]*SearchResponse{
+			SearchCacheKey("companies", params): {Found: 42},
+		}},
+	}
+	if count, err := client.PreviewDelete("companies", "num_employees:<100"); err == nil {
+		t.Errorf("Expected to receive the search error instead of the cached count, received %d", count)
+	}
+}
+
+func TestSearch_vectorOnly(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("q") != "*" || query.Get("vector_query") != "embedding:([0.1,0.2], k:10)" {
+			t.Errorf("Expected to receive a wildcard vector search, received %v", query)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"found": 1, "hits": [{"document": {"id": "1"}, "highlights": [], "vector_distance": 0.19}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	searchResp, err := client.Search("products", SearchParameters{VectorQuery: "embedding:([0.1,0.2], k:10)"})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if distance := searchResp.Hits[0].VectorDistance; distance != 0.19 {
+		t.Errorf("Expected to receive vector distance %v, received %v", 0.19, distance)
+	}
+	if _, err := client.Search("products", SearchParameters{}); err != ErrSearchQueryRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrSearchQueryRequired, err)
+	}
+}
+
+func TestEncodeForm_highlightParameters(t *testing.T) {
+	affixNumTokens := 2
+	opts := SearchParameters{
+		Q:                       "query",
+		QueryBy:                 []string{"title", "description"},
+		IncludeFields:           []string{"title"},
+		AutoHighlightFields:     true,
+		HighlightFields:         []string{"description"},
+		HighlightStartTag:       "<em>",
+		HighlightEndTag:         "</em>",
+		HighlightAffixNumTokens: &affixNumTokens,
+	}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	expected := map[string]string{
+		"highlight_fields":           "description",
+		"highlight_start_tag":        "<em>",
+		"highlight_end_tag":          "</em>",
+		"highlight_affix_num_tokens": "2",
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("Expected %s %q, received %q", name, value, values.Get(name))
+		}
+	}
+	opts = SearchParameters{Q: "query", QueryBy: []string{"title"}}
+	form, _ = opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("highlight_start_tag") || values.Has("highlight_end_tag") {
+		t.Errorf("Expected the highlight tags to be left to the server, received %v", form)
+	}
+}
+
+func TestSearchHighlight_matchedTokens(t *testing.T) {
+	var highlights []SearchHighlight
+	data := `[
+		{"field": "title", "snippet": "<mark>Stark</mark> Industries", "matched_tokens": ["Stark"]},
+		{"field": "tags", "snippets": ["<mark>iron</mark>"], "indices": [1], "matched_tokens": [["iron"], ["man"]]}
+	]`
+	if err := json.Unmarshal([]byte(data), &highlights); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected := []SearchHighlight{
+		{Field: "title", Snippet: "<mark>Stark</mark> Industries", MatchedTokens: []string{"Stark"}},
+		{Field: "tags", Snippets: []string{"<mark>iron</mark>"}, Indices: []int{1}, MatchedTokens: []string{"iron", "man"}},
+	}
+	if !reflect.DeepEqual(highlights, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, highlights)
+	}
+}
+
+func TestSearchResponse_relaxedQuery(t *testing.T) {
+	var searchResponse SearchResponse
+	data := `{
+		"found": 2,
+		"hits": [
+			{"document": {"id": "1"}, "text_match_info": {"tokens_matched": 3, "fields_matched": 1}},
+			{"document": {"id": "2"}, "text_match_info": {"tokens_matched": 2, "fields_matched": 1}}
+		],
+		"request_params": {"q": "stark industries inc"}
+	}`
+	if err := json.Unmarshal([]byte(data), &searchResponse); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !searchResponse.RelaxedQuery {
+		t.Errorf("Expected the query to be relaxed")
+	}
+	if searchResponse.Hits[1].TextMatchInfo.TokensMatched != 2 {
+		t.Errorf("Expected to receive 2 matched tokens, received %v", searchResponse.Hits[1].TextMatchInfo.TokensMatched)
+	}
+	data = `{
+		"found": 1,
+		"hits": [{"document": {"id": "1"}, "text_match_info": {"tokens_matched": 2, "fields_matched": 1}}],
+		"request_params": {"q": "stark industries"}
+	}`
+	searchResponse = SearchResponse{}
+	if err := json.Unmarshal([]byte(data), &searchResponse); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if searchResponse.RelaxedQuery {
+		t.Errorf("Expected the query to not be relaxed")
+	}
+}
+
+func TestEncodeForm_enableAnalytics(t *testing.T) {
+	enableAnalytics := false
+	opts := SearchParameters{Q: "query", QueryBy: []string{"title"}, EnableAnalytics: &enableAnalytics}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if value := values.Get("enable_analytics"); value != "false" {
+		t.Errorf("Expected enable_analytics %q, received %q", "false", value)
+	}
+	opts.EnableAnalytics = nil
+	form, _ = opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("enable_analytics") {
+		t.Errorf("Expected enable_analytics to not be set, received %v", form)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   error
+		unexpected error
+	}{
+		{http.StatusInternalServerError, ErrServerError, ErrServiceUnavailable},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable, ErrServerError},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: test.statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Ready or Lagging"}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		_, searchErr := client.Search(collectionNameTest, SearchParameters{Q: "query", QueryBy: []string{"title"}})
+		_, importErr := client.ImportDocuments(collectionNameTest, []interface{}{testDocument}, "create")
+		for _, err := range []error{searchErr, importErr} {
+			if !errors.Is(err, test.expected) || errors.Is(err, test.unexpected) {
+				t.Errorf("Expected to receive error %v for status %d, received %v", test.expected, test.statusCode, err)
+			}
+			if err != nil && err.Error() != "Not Ready or Lagging" {
+				t.Errorf("Expected to receive the message of Typesense, received %v", err)
+			}
+		}
+	}
+}
+
+func TestSearchResultHit_curated(t *testing.T) {
+	var searchResponse SearchResponse
+	data := `{
+		"found": 2,
+		"hits": [
+			{"document": {"id": "1"}, "curated": true},
+			{"document": {"id": "2"}}
+		]
+	}`
+	if err := json.Unmarshal([]byte(data), &searchResponse); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !searchResponse.Hits[0].Curated || searchResponse.Hits[1].Curated {
+		t.Errorf("Expected only the first hit to be curated, received %v and %v", searchResponse.Hits[0].Curated, searchResponse.Hits[1].Curated)
+	}
+}
+
+func TestEncodeForm_queryByWeights(t *testing.T) {
+	opts := SearchParameters{
+		Q:              "stark",
+		QueryBy:        []string{"title", "body"},
+		QueryByWeights: []int{3, 1},
+		PrefixByField:  []bool{true, false},
+	}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if weights := values.Get("query_by_weights"); weights != "3,1" {
+		t.Errorf("Expected query_by_weights %q, received %q", "3,1", weights)
+	}
+	if prefix := values.Get("prefix"); prefix != "true,false" {
+		t.Errorf("Expected prefix %q, received %q", "true,false", prefix)
+	}
+	opts.QueryByWeights = []int{3}
+	if _, err := opts.encodeForm(); err != ErrQueryWeightsMismatch {
+		t.Errorf("Expected to receive error %v, received %v", ErrQueryWeightsMismatch, err)
+	}
+	opts.QueryByWeights = nil
+	opts.PrefixByField = []bool{true}
+	if _, err := opts.encodeForm(); err != ErrPrefixByFieldMismatch {
+		t.Errorf("Expected to receive error %v, received %v", ErrPrefixByFieldMismatch, err)
+	}
+}
+
+func TestSearchResponse_pagination(t *testing.T) {
+	tests := []struct {
+		found       int
+		perPage     int
+		totalPages  int
+		hasNextPage bool
+	}{
+		{found: 30, perPage: 10, totalPages: 3, hasNextPage: false},
+		{found: 31, perPage: 10, totalPages: 4, hasNextPage: true},
+		{found: 0, perPage: 10, totalPages: 0, hasNextPage: false},
+		{found: 30, perPage: 0, totalPages: 0, hasNextPage: false},
+	}
+	for _, test := range tests {
+		searchResponse := SearchResponse{Found: test.found}
+		if totalPages := searchResponse.TotalPages(test.perPage); totalPages != test.totalPages {
+			t.Errorf("Expected %d pages for %d results of %d per page, received %d", test.totalPages, test.found, test.perPage, totalPages)
+		}
+		if hasNextPage := searchResponse.HasNextPage(3, test.perPage); hasNextPage != test.hasNextPage {
+			t.Errorf("Expected next page %v after page 3 for %d results of %d per page, received %v", test.hasNextPage, test.found, test.perPage, hasNextPage)
+		}
+	}
+	grouped := SearchResponse{Found: 62, FoundGroups: 12}
+	if totalPages := grouped.TotalPages(10); totalPages != 2 {
+		t.Errorf("Expected to page through the groups, received %d pages", totalPages)
+	}
+	page := 3
+	current := SearchParameters{Q: "stark", QueryBy: []string{"company_name"}, Page: &page}
+	next := grouped.NextPageParams(current)
+	if *next.Page != 4 || *current.Page != 3 {
+		t.Errorf("Expected the next page 4 without changing the current page, received %d and %d", *next.Page, *current.Page)
+	}
+	if next = grouped.NextPageParams(SearchParameters{Q: "stark"}); *next.Page != 2 {
+		t.Errorf("Expected the next page of the first page to be 2, received %d", *next.Page)
+	}
+}
+
+func TestEncodeForm_cacheTTL(t *testing.T) {
+	opts := SearchParameters{Q: "sta", QueryBy: []string{"company_name"}, CacheTTL: 120}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if values, _ := url.ParseQuery(form); values.Has("use_cache") || values.Has("cache_ttl") {
+		t.Errorf("Expected to not send the cache parameters without UseCache, received %v", form)
+	}
+	opts.UseCache = true
+	form, _ = opts.encodeForm()
+	values, _ := url.ParseQuery(form)
+	if values.Get("use_cache") != "true" || values.Get("cache_ttl") != "120" {
+		t.Errorf("Expected to send use_cache true and cache_ttl 120, received %v", form)
+	}
+}
+
+func TestEncodeForm_typoTolerance(t *testing.T) {
+	numTypos, minLen1Typo, minLen2Typo, exhaustiveSearch := 1, 5, 9, true
+	opts := SearchParameters{
+		Q:                "SKU-1234",
+		QueryBy:          []string{"sku"},
+		NumTypos:         &numTypos,
+		MinLen1Typo:      &minLen1Typo,
+		MinLen2Typo:      &minLen2Typo,
+		ExhaustiveSearch: &exhaustiveSearch,
+	}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	expected := map[string]string{
+		"num_typos":         "1",
+		"min_len_1typo":     "5",
+		"min_len_2typo":     "9",
+		"exhaustive_search": "true",
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("Expected %s %q, received %q", name, value, values.Get(name))
+		}
+	}
+	for _, invalid := range []int{-1, 3} {
+		numTypos = invalid
+		if _, err := opts.encodeForm(); err != ErrInvalidNumTypos {
+			t.Errorf("Expected to receive error %v for %d typos, received %v", ErrInvalidNumTypos, invalid, err)
+		}
+	}
+}
+
+func TestEncodeForm_infix(t *testing.T) {
+	opts := SearchParameters{Q: "456", QueryBy: []string{"sku", "name"}, Infix: []string{"always", "off"}}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if values, _ := url.ParseQuery(form); values.Get("infix") != "always,off" {
+		t.Errorf("Expected infix %q, received %q", "always,off", values.Get("infix"))
+	}
+	for _, infix := range [][]string{{"always"}, {"always", "sometimes"}} {
+		opts.Infix = infix
+		if _, err := opts.encodeForm(); !errors.Is(err, ErrInvalidInfix) {
+			t.Errorf("Expected to receive error %v for %v, received %v", ErrInvalidInfix, infix, err)
+		}
+	}
+}
+
+func TestEncodeForm_rankingTieBreakers(t *testing.T) {
+	prioritizeExactMatch, prioritizeTokenPosition := true, false
+	opts := SearchParameters{
+		Q:                       "iron man",
+		QueryBy:                 []string{"title", "description"},
+		PrioritizeExactMatch:    &prioritizeExactMatch,
+		PrioritizeTokenPosition: &prioritizeTokenPosition,
+		TextMatchType:           "max_weight",
+	}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	expected := map[string]string{
+		"prioritize_exact_match":    "true",
+		"prioritize_token_position": "false",
+		"text_match_type":           "max_weight",
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("Expected %s %q, received %q", name, value, values.Get(name))
+		}
+	}
+	opts.TextMatchType = "min_score"
+	if _, err := opts.encodeForm(); err != ErrInvalidTextMatchType {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidTextMatchType, err)
+	}
+}
+
+func TestEncodeForm_searchGuardrails(t *testing.T) {
+	opts := SearchParameters{Q: "*", QueryBy: []string{"title"}, LimitHits: 1000, MaxCandidates: 100}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	if value := values.Get("limit_hits"); value != "1000" {
+		t.Errorf("Expected limit_hits %v, received %v", "1000", value)
+	}
+	if value := values.Get("max_candidates"); value != "100" {
+		t.Errorf("Expected max_candidates %v, received %v", "100", value)
+	}
+	opts.LimitHits, opts.MaxCandidates = 0, 0
+	form, _ = opts.encodeForm()
+	if values, _ := url.ParseQuery(form); values.Has("limit_hits") || values.Has("max_candidates") {
+		t.Errorf("Expected limit_hits and max_candidates to not be set")
+	}
+}
+
+func TestEncodeForm_curationToggles(t *testing.T) {
+	enabled, disabled := true, false
+	opts := SearchParameters{
+		Q:                 "iphone",
+		QueryBy:           []string{"title"},
+		EnableSynonyms:    &disabled,
+		FilterCuratedHits: &enabled,
+		EnableOverrides:   &disabled,
+		SynonymPrefix:     &enabled,
+	}
+	form, err := opts.encodeForm()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	values, _ := url.ParseQuery(form)
+	expected := map[string]string{
+		"enable_synonyms":     "false",
+		"filter_curated_hits": "true",
+		"enable_overrides":    "false",
+		"synonym_prefix":      "true",
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("Expected %s %q, received %q", name, value, values.Get(name))
+		}
+	}
+	form, _ = (&SearchParameters{Q: "iphone", QueryBy: []string{"title"}}).encodeForm()
+	values, _ = url.ParseQuery(form)
+	for name := range expected {
+		if values.Has(name) {
+			t.Errorf("Expected %s to not be set", name)
+		}
 	}
 }
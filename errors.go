@@ -1,6 +1,11 @@
 package typesense
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
 
 // ErrConnNotReady is the error that alerts that the connection with the Typesense API
 // could not be established, it can be because of a connection  timeout, a unauthorized
@@ -10,40 +15,306 @@ var ErrConnNotReady = errors.New("typesense connection is not ready")
 // ErrCollectionNotFound returned when Typesense can't find the collection.
 var ErrCollectionNotFound = errors.New("collection was not found")
 
+// ErrOverrideNotFound returned when Typesense can't find the override.
+var ErrOverrideNotFound = errors.New("override was not found")
+
+// ErrSynonymNotFound returned when Typesense can't find the synonym.
+var ErrSynonymNotFound = errors.New("synonym was not found")
+
+// ErrAliasNotFound returned when Typesense can't find the alias.
+var ErrAliasNotFound = errors.New("alias was not found")
+
+// ErrAliasTargetNotFound returned when the user tries to point an alias to a collection that
+// doesn't exist.
+var ErrAliasTargetNotFound = errors.New("the target collection of the alias was not found")
+
+// ErrPresetNotFound returned when Typesense can't find the preset.
+var ErrPresetNotFound = errors.New("preset was not found")
+
 // ErrCollectionNameRequired returned when the user tries to create a collection without a name.
 var ErrCollectionNameRequired = errors.New("collection name is required")
 
+// ErrInvalidCollectionName returned when the collection name is empty or has characters
+// that are not allowed in a collection name.
+var ErrInvalidCollectionName = errors.New("invalid collection name")
+
 // ErrCollectionFieldsRequired returned when the user tries to create a collection without
 // its fields.
 var ErrCollectionFieldsRequired = errors.New("collection fields is required")
 
+// ErrInvalidFieldType returned when a field of the collection schema has a type not supported
+// by Typesense.
+var ErrInvalidFieldType = errors.New("field type is not supported")
+
+// ErrNestedFieldsDisabled returned when the collection schema has object fields without
+// enabling the nested fields.
+var ErrNestedFieldsDisabled = errors.New("object fields require enable_nested_fields")
+
+// ErrInvalidDefaultSortingField returned when the default sorting field of the collection
+// schema is not a required int32, int64 or float field of the schema.
+var ErrInvalidDefaultSortingField = errors.New("default sorting field must be a required numeric field of the schema")
+
+// ErrDuplicateFieldName returned when the collection schema has more than one field with
+// the same name.
+var ErrDuplicateFieldName = errors.New("duplicate field name")
+
+// ErrStemNotSupported returned when the user tries to enable stemming on a field that
+// isn't a string or string[] field.
+var ErrStemNotSupported = errors.New("stem is only supported by string and string[] fields")
+
+// ErrInvalidEmbedConfig returned when the user tries to declare an auto-embedding field
+// that isn't a float[] field or has no source fields or model.
+var ErrInvalidEmbedConfig = errors.New("embed requires a float[] field with source fields and a model name")
+
 // ErrCollectionDuplicate returned when the user tries to create a collection with a name that
 // already exists.
 var ErrCollectionDuplicate = errors.New("a collection with this name already exists")
 
+// ErrCoalescerClosed returned when the user tries to add an update to an UpdateCoalescer
+// that is closed.
+var ErrCoalescerClosed = errors.New("the update coalescer is closed")
+
+// ErrUnsupportedTransport is returned by the calls of a client whose TLS
+// configuration couldn't be applied because its HTTP client or
+// transport is not an *http.Client with an *http.Transport.
+var ErrUnsupportedTransport = errors.New("the TLS configuration requires an *http.Transport")
+
+// ErrSchemaMismatch returned when the user ensures a collection that already exists with a
+// different schema.
+var ErrSchemaMismatch = errors.New("the collection exists with a different schema")
+
+// ErrCollectionAliased returned when the user tries to safely delete a collection that
+// aliases point to.
+var ErrCollectionAliased = errors.New("the collection is the target of aliases")
+
 // ErrNotFound returned when no resource was found for the request.
 var ErrNotFound = errors.New("the resouce you are trying to fetch from Typesense does not exist")
 
+// ErrSearchQueryRequired returned when the user didn't specify a query to search for.
+var ErrSearchQueryRequired = errors.New("query field is required")
+
 // ErrQueryRequired returned when the user didn't specify a query to search for.
-var ErrQueryRequired = errors.New("query field is required")
+//
+// Deprecated: use ErrSearchQueryRequired, ErrQueryRequired is the same error.
+var ErrQueryRequired = ErrSearchQueryRequired
 
 // ErrQueryByRequired returned when the user didn't specfify fields to query by, the url field
 // `query_by` is a required field.
 var ErrQueryByRequired = errors.New("query by field is required")
 
+// ErrInvalidNumTypos returned when the search allows a number of typos other than 0, 1 or 2.
+var ErrInvalidNumTypos = errors.New("num typos must be 0, 1 or 2")
+
+// ErrQueryWeightsMismatch returned when the search has a number of query by weights other than
+// the number of query by fields.
+var ErrQueryWeightsMismatch = errors.New("query by weights must have one weight per query by field")
+
+// ErrPrefixByFieldMismatch returned when the search has a number of prefix values other than
+// the number of query by fields.
+var ErrPrefixByFieldMismatch = errors.New("prefix by field must have one value per query by field")
+
+// ErrDocumentNotFound returned when Typesense can't find the document.
+var ErrDocumentNotFound = errors.New("document was not found")
+
+// ErrIDFieldNotFound returned when the document the user is trying to write has no id and
+// no value in the id field of the client.
+var ErrIDFieldNotFound = errors.New("the document has no id and no value in the id field")
+
+// ErrEmptyUpdate returned when the user tries to update a document without any field.
+var ErrEmptyUpdate = errors.New("the update has no fields")
+
+// ErrInvalidImportAction returned when the user tries to import documents with an action other
+// than create, upsert, update or emplace.
+var ErrInvalidImportAction = errors.New("import action must be create, upsert, update or emplace")
+
+// ErrPartialImport returned when some of the documents of an import were rejected, the
+// results of the import tell which ones.
+var ErrPartialImport = errors.New("some documents failed to import")
+
+// ErrVersionConflict returned when the user tries to conditionally update a document whose
+// version differs from the expected one.
+var ErrVersionConflict = errors.New("the document version does not match the expected version")
+
+// ErrInvalidInfix returned when the user tries to search with infix modes other than off,
+// always or fallback, or without one mode per query by field.
+var ErrInvalidInfix = errors.New("infix must have one mode of off, always or fallback per query by field")
+
+// ErrInvalidDropTokensMode returned when the user tries to search with a drop tokens mode other
+// than right_to_left, left_to_right or both_sides:N.
+var ErrInvalidDropTokensMode = errors.New("drop tokens mode must be right_to_left, left_to_right or both_sides:N")
+
+// ErrInvalidFacetStrategy returned when the user tries to search with a facet strategy other
+// than exhaustive, top_values or automatic.
+var ErrInvalidFacetStrategy = errors.New("facet strategy must be exhaustive, top_values or automatic")
+
+// ErrInvalidTextMatchType returned when the user tries to search with a text match type other
+// than max_score, max_weight or sum_score.
+var ErrInvalidTextMatchType = errors.New("text match type must be max_score, max_weight or sum_score")
+
+// ErrGroupByRequired returned when the user tries to make a grouped search without group by
+// fields.
+var ErrGroupByRequired = errors.New("group by fields are required to make a grouped search")
+
+// ErrInvalidDirtyValues returned when the user tries to write documents with a dirty values
+// mode other than coerce_or_reject, coerce_or_drop, drop or reject.
+var ErrInvalidDirtyValues = errors.New("dirty values must be coerce_or_reject, coerce_or_drop, drop or reject")
+
+// ErrFilterRequired returned when the user tries to delete documents by query without a filter.
+var ErrFilterRequired = errors.New("filter by field is required to delete documents by query")
+
+// ErrTenantFilterRequired returned when the user tries to generate the scoped key of a tenant
+// without a filter.
+var ErrTenantFilterRequired = errors.New("filter by field is required to generate a tenant key")
+
+// ErrBrowseFilterRequired returned when the user tries to browse a collection without filtering
+// or sorting the documents.
+var ErrBrowseFilterRequired = errors.New("filter by or sort by field is required to browse")
+
+// ErrVectorRequired returned when the user tries to build a vector query without a vector.
+var ErrVectorRequired = errors.New("vector query requires a non-empty vector")
+
+// ErrInvalidVectorK returned when the user tries to build a vector query with a non-positive
+// number of neighbors.
+var ErrInvalidVectorK = errors.New("vector query k must be greater than zero")
+
+// ErrSnapshotPathRequired returned when the user tries to create a snapshot without a path.
+var ErrSnapshotPathRequired = errors.New("snapshot path is required")
+
+// ErrSnapshotInProgress returned when the user tries to create a snapshot while another
+// snapshot is being created.
+var ErrSnapshotInProgress = errors.New("a snapshot is already in progress")
+
+// ErrTooManyRequests returned when Typesense rate limits the request. When the response
+// tells how long to wait, the error is a *RateLimitError wrapping it.
+var ErrTooManyRequests = errors.New("too many requests")
+
 // ErrUnauthorized returned when the API key does not match the Typesense API key.
 var ErrUnauthorized = errors.New("the api key does not match the Typesense api key")
 
+// ErrServerError returned when Typesense fails with an internal server error (500). The
+// returned *APIError holds the message of Typesense and matches it with errors.Is.
+var ErrServerError = errors.New("typesense internal server error")
+
+// ErrServiceUnavailable returned when the Typesense node is not ready to serve the request
+// (503), e.g. while it elects a leader or lags behind it. The returned *APIError holds
+// the message of Typesense and matches it with errors.Is.
+var ErrServiceUnavailable = errors.New("typesense service unavailable")
+
 // ErrDuplicateID returned when the document the user is trying to index has an id that is already
 // in the collection.
 var ErrDuplicateID = errors.New("the document you are trying to index has an id that already exists in the collection")
 
-// APIError is an error returned from the API.
+// ErrAPIKeyNotFound returned when the user tries to delete an API key that doesn't exist.
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrInvalidSearchKey returned when the user tries to generate a scoped search key from a
+// search key that is too short.
+var ErrInvalidSearchKey = errors.New("search key is too short to generate a scoped key")
+
+// ErrInvalidScopedKey returned when the user tries to decode a malformed scoped search key.
+var ErrInvalidScopedKey = errors.New("scoped search key is malformed")
+
+// ErrResponseTooLarge returned when a response body is bigger than the maximum response
+// size of the client.
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum response size")
+
+// APIError is an error response of the API that has no matching sentinel error, e.g. a
+// bad request. It is returned as a *APIError, use errors.As to inspect its status code.
 type APIError struct {
-	Message string `json:"string"`
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
 }
 
 // Error returns a string representation of the error.
-func (e APIError) Error() string {
+func (e *APIError) Error() string {
 	return e.Message
 }
+
+// Is reports whether the error matches the sentinel error of its status code, so
+// errors.Is(err, ErrUnauthorized), errors.Is(err, ErrNotFound), errors.Is(err,
+// ErrServerError) and errors.Is(err, ErrServiceUnavailable) hold for the matching status
+// codes.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrServerError:
+		return e.StatusCode == http.StatusInternalServerError
+	case ErrServiceUnavailable:
+		return e.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// ValidationError is a problem with a single field of a collection schema rejected by
+// Typesense.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+// SchemaValidationError is returned when Typesense rejects a collection schema because of
+// some of its fields, Fields tells which ones and why. It wraps the *APIError of the
+// response.
+type SchemaValidationError struct {
+	Fields []ValidationError
+	err    *APIError
+}
+
+// Error returns a string representation of the error.
+func (e *SchemaValidationError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the *APIError of the response.
+func (e *SchemaValidationError) Unwrap() error {
+	return e.err
+}
+
+// DocumentError is returned when Typesense rejects a document, e.g. because a field
+// doesn't match the type declared in the schema. Field is the field the message refers
+// to, empty when it refers to none, and Document is the JSON of the rejected document.
+// It wraps the *APIError of the response, if any.
+type DocumentError struct {
+	Message  string
+	Field    string
+	Document string
+	err      *APIError
+}
+
+// Error returns a string representation of the error.
+func (e *DocumentError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the *APIError of the response, nil for the errors of the imported
+// documents.
+func (e *DocumentError) Unwrap() error {
+	if e.err == nil {
+		return nil
+	}
+	return e.err
+}
+
+// RateLimitError is returned when Typesense rate limits a request and tells with the
+// Retry-After header how long to wait before retrying. It wraps ErrTooManyRequests.
+type RateLimitError struct {
+	retryAfter time.Duration
+}
+
+// Error returns a string representation of the error.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", ErrTooManyRequests, e.retryAfter)
+}
+
+// Unwrap returns ErrTooManyRequests.
+func (e *RateLimitError) Unwrap() error {
+	return ErrTooManyRequests
+}
+
+// RetryAfter returns how long to wait before retrying the request.
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
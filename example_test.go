@@ -2,6 +2,7 @@ package typesense
 
 import (
 	"log"
+	"time"
 )
 
 // Example of an actual program that can connect to the Typesense
@@ -17,7 +18,7 @@ func Example() {
 			Protocol: "http",
 			APIKey:   "api-key",
 		},
-		2,
+		WithTimeout(2*time.Second),
 	)
 	if err := client.Ping(); err != nil {
 		panic(err)
@@ -95,7 +96,10 @@ func Example() {
 	}
 
 	// Searches for the document by title and prints it.
-	search, err := client.Search("books", "The Go Programming Language", []string{"title"}, nil)
+	search, err := client.Search("books", SearchParameters{
+		Q:       "The Go Programming Language",
+		QueryBy: []string{"title"},
+	})
 	if err != nil {
 		log.Printf("couldn't search for books: %v", err)
 	}
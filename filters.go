@@ -0,0 +1,302 @@
+package typesense
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterBuilder builds filter_by expressions. The conditions added to
+// the builder are combined with the && operator, e.g.
+//
+//	NewFilterBuilder().GreaterThan("price", 10).LessThanOrEqual("price", 99.9).String()
+//
+// produces price:>10 && price:<=99.9.
+type FilterBuilder struct {
+	conditions []string
+}
+
+// NewFilterBuilder creates an empty filter builder.
+func NewFilterBuilder() *FilterBuilder {
+	return &FilterBuilder{}
+}
+
+// GreaterThan adds the condition field:>value.
+func (b *FilterBuilder) GreaterThan(field string, value interface{}) *FilterBuilder {
+	return b.compare(field, ">", value)
+}
+
+// GreaterThanOrEqual adds the condition field:>=value.
+func (b *FilterBuilder) GreaterThanOrEqual(field string, value interface{}) *FilterBuilder {
+	return b.compare(field, ">=", value)
+}
+
+// LessThan adds the condition field:<value.
+func (b *FilterBuilder) LessThan(field string, value interface{}) *FilterBuilder {
+	return b.compare(field, "<", value)
+}
+
+// LessThanOrEqual adds the condition field:<=value.
+func (b *FilterBuilder) LessThanOrEqual(field string, value interface{}) *FilterBuilder {
+	return b.compare(field, "<=", value)
+}
+
+// Equals adds the condition field:=value. String values are quoted in
+// backticks so values with special characters, e.g. commas or &&,
+// match as is.
+func (b *FilterBuilder) Equals(field string, value interface{}) *FilterBuilder {
+	b.conditions = append(b.conditions, fmt.Sprintf("%s:=%s", field, formatFilterValue(value)))
+	return b
+}
+
+// In adds the condition that the field matches any of the values,
+// field:=[value1, value2], quoting the values like Equals.
+func (b *FilterBuilder) In(field string, values []string) *FilterBuilder {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteFilterValue(value)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf("%s:=[%s]", field, strings.Join(quoted, ", ")))
+	return b
+}
+
+// Range adds the condition that the numeric field is between min and
+// max, both inclusive, field:[min..max].
+func (b *FilterBuilder) Range(field string, min, max interface{}) *FilterBuilder {
+	b.conditions = append(b.conditions, fmt.Sprintf("%s:[%s..%s]", field, formatNumber(min), formatNumber(max)))
+	return b
+}
+
+// And adds the conditions of all the builders as a single group, e.g.
+// to nest a group of conditions in Or.
+func (b *FilterBuilder) And(builders ...*FilterBuilder) *FilterBuilder {
+	return b.group(" && ", builders)
+}
+
+// Or adds a group of conditions that matches when the conditions of
+// any of the builders match, e.g.
+//
+//	NewFilterBuilder().Equals("in_stock", true).Or(
+//		NewFilterBuilder().Equals("brand", "Acme"),
+//		NewFilterBuilder().LessThan("price", 10),
+//	)
+//
+// produces in_stock:=true && (brand:=`Acme` || price:<10).
+func (b *FilterBuilder) Or(builders ...*FilterBuilder) *FilterBuilder {
+	return b.group(" || ", builders)
+}
+
+func (b *FilterBuilder) group(operator string, builders []*FilterBuilder) *FilterBuilder {
+	var expressions []string
+	for _, builder := range builders {
+		if len(builder.conditions) == 1 {
+			expressions = append(expressions, builder.conditions[0])
+		} else if len(builder.conditions) > 1 {
+			expressions = append(expressions, "("+builder.String()+")")
+		}
+	}
+	if len(expressions) == 1 {
+		b.conditions = append(b.conditions, expressions[0])
+	} else if len(expressions) > 1 {
+		b.conditions = append(b.conditions, "("+strings.Join(expressions, operator)+")")
+	}
+	return b
+}
+
+func (b *FilterBuilder) compare(field, operator string, value interface{}) *FilterBuilder {
+	b.conditions = append(b.conditions, fmt.Sprintf("%s:%s%s", field, operator, formatNumber(value)))
+	return b
+}
+
+// String returns the filter_by expression.
+func (b *FilterBuilder) String() string {
+	return strings.Join(b.conditions, " && ")
+}
+
+// GeoRadius returns the filter_by condition that matches the documents
+// with the geopoint field within radiusKm kilometers of the point, e.g.
+// location:(48.85, 2.35, 5 km).
+func GeoRadius(field string, lat, lng, radiusKm float64) string {
+	return fmt.Sprintf("%s:(%s, %s, %s km)", field, formatNumber(lat), formatNumber(lng), formatNumber(radiusKm))
+}
+
+// GeoSortBy returns the sort_by expression that sorts the documents by
+// the distance of the geopoint field to the point in the direction, asc
+// or desc, e.g. location(48.85, 2.35):asc. The distance of each hit is
+// returned in its GeoDistanceMeters.
+func GeoSortBy(field string, lat, lng float64, direction string) string {
+	return fmt.Sprintf("%s(%s, %s):%s", field, formatNumber(lat), formatNumber(lng), direction)
+}
+
+// formatNumber formats integers and floats without exponents or
+// trailing zeros, as expected by Typesense.
+func formatNumber(value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatFilterValue formats a value of a condition, quoting strings.
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return quoteFilterValue(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return formatNumber(v)
+	}
+}
+
+// quoteFilterValue quotes a string value in backticks, escaping the
+// backticks of the value.
+func quoteFilterValue(value string) string {
+	return "`" + strings.ReplaceAll(value, "`", "\\`") + "`"
+}
+
+// FilterSyntaxError is a syntax error of a filter_by expression found
+// by ValidateFilterBy, the position is the byte offset of the error in
+// the expression.
+type FilterSyntaxError struct {
+	Position int
+	Message  string
+}
+
+// Error returns a string representation of the error.
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter_by at position %d: %s", e.Position, e.Message)
+}
+
+// filterOperators are the comparison operators that can follow the
+// colon of a condition, longest first.
+var filterOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// ValidateFilterBy makes a basic syntactic check of a filter_by
+// expression before it is sent to Typesense. It checks that the
+// parentheses, brackets and backticks are balanced, that conditions
+// are joined by && or || and that every condition has a field, a
+// recognized operator and a value. It returns a *FilterSyntaxError
+// pointing at the offending position. A valid expression is not
+// guaranteed to be accepted by Typesense, e.g. the fields are not
+// checked against the collection schema.
+func ValidateFilterBy(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return &FilterSyntaxError{0, "empty expression"}
+	}
+	type opening struct {
+		position int
+		char     byte
+	}
+	var openings []opening
+	conditionStart := 0
+	backtickStart := -1
+	for i := 0; i < len(expr); i++ {
+		char := expr[i]
+		if backtickStart >= 0 {
+			if char == '\\' {
+				i++
+			} else if char == '`' {
+				backtickStart = -1
+			}
+			continue
+		}
+		switch char {
+		case '`':
+			backtickStart = i
+		case '(', '[':
+			openings = append(openings, opening{i, char})
+		case ')', ']':
+			expected := byte('(')
+			if char == ']' {
+				expected = '['
+			}
+			if len(openings) == 0 || openings[len(openings)-1].char != expected {
+				return &FilterSyntaxError{i, fmt.Sprintf("unexpected %q", char)}
+			}
+			openings = openings[:len(openings)-1]
+		case '&', '|':
+			if i+1 >= len(expr) || expr[i+1] != char {
+				return &FilterSyntaxError{i, fmt.Sprintf("unexpected %q, use %c%c", char, char, char)}
+			}
+			if err := validateFilterCondition(expr[conditionStart:i], conditionStart); err != nil {
+				return err
+			}
+			i++
+			conditionStart = i + 1
+		}
+	}
+	if backtickStart >= 0 {
+		return &FilterSyntaxError{backtickStart, "unterminated backtick"}
+	}
+	if len(openings) > 0 {
+		last := openings[len(openings)-1]
+		return &FilterSyntaxError{last.position, fmt.Sprintf("unclosed %q", last.char)}
+	}
+	return validateFilterCondition(expr[conditionStart:], conditionStart)
+}
+
+// validateFilterCondition validates a single condition of a filter_by
+// expression, offset is the position of the condition in the expression.
+func validateFilterCondition(condition string, offset int) error {
+	start, end := 0, len(condition)
+	for start < end && (condition[start] == ' ' || condition[start] == '(') {
+		start++
+	}
+	for start < end && (condition[end-1] == ' ' ||
+		condition[end-1] == ')' && strings.Count(condition[start:end], ")") > strings.Count(condition[start:end], "(")) {
+		end--
+	}
+	if start == end {
+		return &FilterSyntaxError{offset + start, "missing condition"}
+	}
+	colon := strings.IndexByte(condition[start:end], ':')
+	if colon < 0 {
+		return &FilterSyntaxError{offset + start, "missing ':' between field and value"}
+	}
+	colon += start
+	if strings.TrimSpace(condition[start:colon]) == "" {
+		return &FilterSyntaxError{offset + colon, "missing field name"}
+	}
+	valueStart := colon + 1
+	for valueStart < end && condition[valueStart] == ' ' {
+		valueStart++
+	}
+	for _, operator := range filterOperators {
+		if strings.HasPrefix(condition[valueStart:end], operator) {
+			valueStart += len(operator)
+			break
+		}
+	}
+	if valueStart < end && strings.IndexByte("=!<>", condition[valueStart]) >= 0 {
+		return &FilterSyntaxError{offset + valueStart, "unrecognized operator"}
+	}
+	if strings.TrimSpace(condition[valueStart:end]) == "" {
+		return &FilterSyntaxError{offset + valueStart, "missing value"}
+	}
+	return nil
+}
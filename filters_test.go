@@ -0,0 +1,120 @@
+package typesense
+
+import "testing"
+
+func TestFilterBuilder_comparisons(t *testing.T) {
+	tests := []struct {
+		builder  *FilterBuilder
+		expected string
+	}{
+		{NewFilterBuilder().GreaterThan("num_employees", 100), "num_employees:>100"},
+		{NewFilterBuilder().GreaterThanOrEqual("num_employees", int64(100)), "num_employees:>=100"},
+		{NewFilterBuilder().LessThan("created_at", int32(1600000000)), "created_at:<1600000000"},
+		{NewFilterBuilder().LessThanOrEqual("price", 99.9), "price:<=99.9"},
+		{NewFilterBuilder().GreaterThan("price", float32(0.5)), "price:>0.5"},
+		{NewFilterBuilder().GreaterThan("price", 10).LessThanOrEqual("price", 1e6), "price:>10 && price:<=1000000"},
+	}
+	for _, test := range tests {
+		if filterBy := test.builder.String(); filterBy != test.expected {
+			t.Errorf("Expected to receive %v, received %v", test.expected, filterBy)
+		}
+	}
+}
+
+func TestValidateFilterBy(t *testing.T) {
+	validFilters := []string{
+		"num_employees:>100",
+		"country:=US && num_employees:[100..500]",
+		"(country:US || country:UK) && tags:=[`a && b`, c]",
+		"location:(48.90615, 2.34358, 5.1 km)",
+		"name:!=Acme",
+	}
+	for _, filter := range validFilters {
+		if err := ValidateFilterBy(filter); err != nil {
+			t.Errorf("Expected %q to be valid, received %v", filter, err)
+		}
+	}
+}
+
+func TestValidateFilterBy_malformed(t *testing.T) {
+	tests := []struct {
+		filter   string
+		position int
+	}{
+		{"(country:US || country:UK", 0},
+		{"country:US)", 10},
+		{"tags:[a, b", 5},
+		{"country:US & num_employees:>100", 11},
+		{"country:US && ", 14},
+		{"country US", 0},
+		{"num_employees:=>100", 15},
+		{"country:", 8},
+		{"name:=`Acme", 6},
+		{":US", 0},
+	}
+	for _, test := range tests {
+		err := ValidateFilterBy(test.filter)
+		syntaxErr, ok := err.(*FilterSyntaxError)
+		if !ok {
+			t.Errorf("Expected a syntax error for %q, received %v", test.filter, err)
+			continue
+		}
+		if syntaxErr.Position != test.position {
+			t.Errorf("Expected the error of %q at position %d, received %v", test.filter, test.position, syntaxErr)
+		}
+	}
+}
+
+func TestFilterBuilder_dsl(t *testing.T) {
+	tests := []struct {
+		builder  *FilterBuilder
+		expected string
+	}{
+		{NewFilterBuilder().Equals("in_stock", true), "in_stock:=true"},
+		{NewFilterBuilder().Equals("brand", "Acme, Inc."), "brand:=`Acme, Inc.`"},
+		{NewFilterBuilder().Equals("title", "the `best` one"), "title:=`the \\`best\\` one`"},
+		{NewFilterBuilder().Equals("year", 2020), "year:=2020"},
+		{NewFilterBuilder().In("country", []string{"US", "UK"}), "country:=[`US`, `UK`]"},
+		{NewFilterBuilder().Range("price", 10, 99.5), "price:[10..99.5]"},
+		{
+			NewFilterBuilder().Equals("in_stock", true).Or(
+				NewFilterBuilder().Equals("brand", "Acme"),
+				NewFilterBuilder().LessThan("price", 10),
+			),
+			"in_stock:=true && (brand:=`Acme` || price:<10)",
+		},
+		{
+			NewFilterBuilder().Or(
+				NewFilterBuilder().And(
+					NewFilterBuilder().Equals("brand", "Acme"),
+					NewFilterBuilder().GreaterThan("rating", 4),
+				),
+				NewFilterBuilder().Equals("featured", true),
+			),
+			"((brand:=`Acme` && rating:>4) || featured:=true)",
+		},
+		{NewFilterBuilder().Or(NewFilterBuilder().Equals("a", 1)), "a:=1"},
+	}
+	for _, test := range tests {
+		filter := test.builder.String()
+		if filter != test.expected {
+			t.Errorf("Expected to receive %s, received %s", test.expected, filter)
+		}
+		if err := ValidateFilterBy(filter); err != nil {
+			t.Errorf("Expected %s to be valid, received %v", filter, err)
+		}
+	}
+}
+
+func TestGeoRadius(t *testing.T) {
+	filterBy := GeoRadius("location", 48.85293, 2.35005, 5)
+	if expected := "location:(48.85293, 2.35005, 5 km)"; filterBy != expected {
+		t.Errorf("Expected to receive %q, received %q", expected, filterBy)
+	}
+	if err := ValidateFilterBy(filterBy); err != nil {
+		t.Errorf("Expected the geo filter to be valid, received %v", err)
+	}
+	if sortBy := GeoSortBy("location", 48.85293, 2.35005, "asc"); sortBy != "location(48.85293, 2.35005):asc" {
+		t.Errorf("Expected to receive %q, received %q", "location(48.85293, 2.35005):asc", sortBy)
+	}
+}
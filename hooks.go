@@ -0,0 +1,86 @@
+package typesense
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a request the client is about to make, it is
+// passed to the OnRequest hook.
+type RequestInfo struct {
+	// Context is the context of the call, e.g. to parent the spans of
+	// a tracer.
+	Context context.Context
+	Method  string
+	URL     string
+
+	// Attempt is the attempt of the request, starting at 1, it is
+	// greater for the retries of the request.
+	Attempt int
+}
+
+// ResponseInfo describes the outcome of a request made by the client,
+// it is passed to the OnResponse hook.
+type ResponseInfo struct {
+	RequestInfo
+
+	// StatusCode is the status code of the response, zero when the
+	// request failed with Err.
+	StatusCode int
+
+	// Duration is the time from the start of the request to the
+	// reception of the response headers, reading the body is not
+	// included.
+	Duration time.Duration
+
+	// Err is the transport error of the request, if any.
+	Err error
+}
+
+// WithOnRequest sets a hook called before each HTTP request the client
+// makes, including the retries, e.g. to trace the calls. It is called
+// synchronously, so it must be fast and safe for concurrent use.
+func WithOnRequest(hook func(RequestInfo)) ClientOption {
+	return func(c *Client) {
+		c.onRequest = hook
+	}
+}
+
+// WithOnResponse sets a hook called after each HTTP request the client
+// makes, including the retries, with its status code and duration,
+// e.g. to record the latency of each endpoint. The hook can't access
+// the body of the response, which is left to the method that made the
+// request. It is called synchronously, so it must be fast and safe for
+// concurrent use.
+func WithOnResponse(hook func(ResponseInfo)) ClientOption {
+	return func(c *Client) {
+		c.onResponse = hook
+	}
+}
+
+// doRequest makes the request with the HTTP client of the client,
+// calling the request and response hooks around it and logging it.
+func (c *Client) doRequest(req *http.Request, attempt int) (*http.Response, error) {
+	info := RequestInfo{
+		Context: req.Context(),
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Attempt: attempt,
+	}
+	if c.onRequest != nil {
+		c.onRequest(info)
+	}
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	response := ResponseInfo{RequestInfo: info, Duration: time.Since(start), Err: err}
+	if resp != nil {
+		response.StatusCode = resp.StatusCode
+		c.lastNode.Store(c.nodeForURL(req.URL))
+	}
+	c.logResponse(response, req.Header.Get(defaultHeaderKey))
+	if c.onResponse != nil {
+		c.onResponse(response)
+	}
+	return resp, err
+}
@@ -0,0 +1,51 @@
+package typesense
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestHooks(t *testing.T) {
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusOK}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[0]
+		statusCodes = statusCodes[1:]
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name": "companies", "num_documents": 0}`)),
+		}, nil
+	}
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	WithRetry(2, time.Millisecond)(&client)
+	WithOnRequest(func(info RequestInfo) {
+		requests = append(requests, info)
+	})(&client)
+	WithOnResponse(func(info ResponseInfo) {
+		responses = append(responses, info)
+	})(&client)
+	if _, err := client.RetrieveCollection("companies"); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(requests) != 2 || len(responses) != 2 {
+		t.Fatalf("Expected the hooks to be called for each attempt, received %v requests and %v responses", len(requests), len(responses))
+	}
+	for i, response := range responses {
+		if response.Method != http.MethodGet || response.URL != "http://localhost:8108/collections/companies" || response.Attempt != i+1 {
+			t.Errorf("Expected to receive the request of attempt %v, received %+v", i+1, response.RequestInfo)
+		}
+		if response.RequestInfo != requests[i] {
+			t.Errorf("Expected the response to describe the request %+v, received %+v", requests[i], response.RequestInfo)
+		}
+	}
+	if responses[0].StatusCode != http.StatusServiceUnavailable || responses[1].StatusCode != http.StatusOK {
+		t.Errorf("Expected to receive the status codes 503 and 200, received %v and %v", responses[0].StatusCode, responses[1].StatusCode)
+	}
+}
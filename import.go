@@ -0,0 +1,299 @@
+package typesense
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ImportResult is the result of importing a single document, the
+// results of an import are in the same order of the documents.
+type ImportResult struct {
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	Document string `json:"document,omitempty"`
+}
+
+// DocumentError returns the error of the failed import as a
+// *DocumentError with the field its message refers to, e.g. to group
+// the failures by field, or nil when the document was imported.
+func (r ImportResult) DocumentError() *DocumentError {
+	if r.Success {
+		return nil
+	}
+	return &DocumentError{Message: r.Error, Field: messageField(r.Error), Document: r.Document}
+}
+
+// ImportDocuments imports the documents into the collection in a single
+// request, encoding them as newline delimited JSON. The action is one of
+// create, upsert, update or emplace. The results are in the same order
+// of the documents. When some of the documents are rejected it returns
+// all the results with an error wrapping ErrPartialImport, the failed
+// results can be picked with FailedImports.
+func (c *Client) ImportDocuments(collectionName string, documents []interface{}, action string, opts ...CallOption) ([]ImportResult, error) {
+	if !validImportAction(action) {
+		return nil, ErrInvalidImportAction
+	}
+	body, err := encodeJSONL(c, documents)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.importJSONL(collectionName, action, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return results, partialImportError(results)
+}
+
+// ImportDocumentsReader imports the documents of the reader, already
+// encoded as newline delimited JSON, into the collection in a single
+// request, e.g. from a JSONL file. The reader is streamed as the body
+// of the request, so the documents are never loaded into memory nor
+// encoded again, and the request is never retried. The action and the
+// results are the same as in ImportDocuments.
+func (c *Client) ImportDocumentsReader(collectionName string, r io.Reader, action string, opts ...CallOption) ([]ImportResult, error) {
+	if !validImportAction(action) {
+		return nil, ErrInvalidImportAction
+	}
+	results, err := c.importJSONL(collectionName, action, nil, append(opts, withBodyReader(r))...)
+	if err != nil {
+		return nil, err
+	}
+	return results, partialImportError(results)
+}
+
+// partialImportError returns an error wrapping ErrPartialImport when
+// some of the results failed.
+func partialImportError(results []ImportResult) error {
+	if failed := FailedImports(results); len(failed) > 0 {
+		return fmt.Errorf("%w: %d of %d documents", ErrPartialImport, len(failed), len(results))
+	}
+	return nil
+}
+
+// FailedImports returns the results of the documents that failed to
+// import.
+func FailedImports(results []ImportResult) []ImportResult {
+	var failed []ImportResult
+	for _, result := range results {
+		if !result.Success {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// defaultImportBatchSize is the number of documents imported in each
+// request by the batched imports, unless it is changed with
+// WithImportBatchSize.
+const defaultImportBatchSize = 40
+
+// WithImportBatchSize sets the number of documents imported in each
+// request by the batched imports, e.g. ImportDocumentsProgress.
+func WithImportBatchSize(batchSize int) ClientOption {
+	return func(c *Client) {
+		c.importBatchSize = batchSize
+	}
+}
+
+// ImportDocumentsProgress imports the documents into the collection
+// like ImportDocuments, in batches of one request each, calling
+// onProgress after each batch with the number of documents imported so
+// far and the total number of documents. The import stops at the first
+// batch that fails, returning the results of the previous batches. When
+// some of the documents are rejected it imports the other batches and
+// returns all the results with an error wrapping ErrPartialImport.
+func (c *Client) ImportDocumentsProgress(collectionName string, documents []interface{}, action string, onProgress func(done, total int), opts ...CallOption) ([]ImportResult, error) {
+	if !validImportAction(action) {
+		return nil, ErrInvalidImportAction
+	}
+	batchSize := c.importBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	results := make([]ImportResult, 0, len(documents))
+	for start := 0; start < len(documents); start += batchSize {
+		end := start + batchSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+		body, err := encodeJSONL(c, documents[start:end])
+		if err != nil {
+			return results, err
+		}
+		batchResults, err := c.importJSONL(collectionName, action, body, opts...)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, batchResults...)
+		if onProgress != nil {
+			onProgress(end, len(documents))
+		}
+	}
+	return results, partialImportError(results)
+}
+
+// ImportDocumentsStream imports the documents received from the docs
+// channel into the collection with the given action, in batches of one
+// request each, e.g. to pipe the rows of a database cursor with bounded
+// memory. The results of the documents of each batch are sent to the
+// returned channel, in the order of the documents, once the batch is
+// imported. A document waits for its batch to fill up or for docs to be
+// closed before it is imported, see WithImportBatchSize.
+//
+// When a batch fails as a whole, e.g. the collection doesn't exist,
+// each of its documents gets a failed result with the error and the
+// import goes on with the next batch. The returned channel is closed
+// once docs is closed and drained, or when ctx is cancelled, which
+// also cancels the import in flight.
+func (c *Client) ImportDocumentsStream(ctx context.Context, collectionName string, docs <-chan interface{}, action string, opts ...CallOption) (<-chan ImportResult, error) {
+	if !validImportAction(action) {
+		return nil, ErrInvalidImportAction
+	}
+	batchSize := c.importBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	opts = append(opts, WithContext(ctx))
+	results := make(chan ImportResult)
+	go func() {
+		defer close(results)
+		batch := make([]interface{}, 0, batchSize)
+		importBatch := func() bool {
+			batchResults, err := c.importBatch(collectionName, action, batch, opts...)
+			batch = batch[:0]
+			for _, result := range batchResults {
+				if err != nil {
+					result = ImportResult{Error: err.Error()}
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case document, ok := <-docs:
+				if !ok {
+					if len(batch) > 0 {
+						importBatch()
+					}
+					return
+				}
+				batch = append(batch, document)
+				if len(batch) == batchSize && !importBatch() {
+					return
+				}
+			}
+		}
+	}()
+	return results, nil
+}
+
+// importBatch imports the batch of documents, it returns one result per
+// document even when it fails.
+func (c *Client) importBatch(collectionName, action string, batch []interface{}, opts ...CallOption) ([]ImportResult, error) {
+	failed := make([]ImportResult, len(batch))
+	body, err := encodeJSONL(c, batch)
+	if err != nil {
+		return failed, err
+	}
+	results, err := c.importJSONL(collectionName, action, body, opts...)
+	if err != nil {
+		return failed, err
+	}
+	if len(results) != len(batch) {
+		return failed, fmt.Errorf("received %d import results for %d documents", len(results), len(batch))
+	}
+	return results, nil
+}
+
+func validImportAction(action string) bool {
+	switch action {
+	case "create", "upsert", "update", "emplace":
+		return true
+	default:
+		return false
+	}
+}
+
+// encodeJSONL marshals the documents as newline delimited JSON.
+func encodeJSONL[T any](c *Client, documents []T) ([]byte, error) {
+	var body bytes.Buffer
+	for i, document := range documents {
+		documentJSON, err := c.marshalNewDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't marshal document %d: %w", i, err)
+		}
+		body.Write(documentJSON)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+// importJSONL imports the newline delimited JSON documents into the
+// collection with the given action.
+func (c *Client) importJSONL(collectionName, action string, body []byte, opts ...CallOption) ([]ImportResult, error) {
+	method := http.MethodPost
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/documents/import?%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		url.Values{"action": []string{action}}.Encode(),
+	)
+	resp, err := c.apiCall(method, url, body, append(opts, withUnlimitedResponse(), withCompressibleBody())...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var results []ImportResult
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var result ImportResult
+		if err := decoder.Decode(&result); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ImportTyped imports the typed documents into the collection with the
+// given action like ImportDocuments, without copying them into a
+// []interface{} first. The results and the errors are the same as in
+// ImportDocuments.
+func ImportTyped[T any](c *Client, collectionName string, documents []T, action string, opts ...CallOption) ([]ImportResult, error) {
+	if !validImportAction(action) {
+		return nil, ErrInvalidImportAction
+	}
+	body, err := encodeJSONL(c, documents)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.importJSONL(collectionName, action, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return results, partialImportError(results)
+}
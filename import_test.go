@@ -0,0 +1,432 @@
+package typesense
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testCompany struct {
+	ID           string `json:"id"`
+	CompanyName  string `json:"company_name"`
+	NumEmployees int    `json:"num_employees"`
+}
+
+func TestImportTyped(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/collections/companies/documents/import" {
+			t.Errorf("Expected to request the import endpoint, requested %v", req.URL.Path)
+		}
+		if action := req.URL.Query().Get("action"); action != "upsert" {
+			t.Errorf("Expected to import with action %v, imported with %v", "upsert", action)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		expected := `{"id":"1","company_name":"Stark Industries","num_employees":5215}` + "\n" +
+			`{"id":"2","company_name":"Acme","num_employees":10}` + "\n"
+		if string(body) != expected {
+			t.Errorf("Expected to import %q, imported %q", expected, string(body))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"success": true}
+{"success": false, "error": "Bad JSON.", "document": "{}"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	companies := []testCompany{
+		{ID: "1", CompanyName: "Stark Industries", NumEmployees: 5215},
+		{ID: "2", CompanyName: "Acme", NumEmployees: 10},
+	}
+	results, err := ImportTyped(&client, "companies", companies, "upsert")
+	if !errors.Is(err, ErrPartialImport) {
+		t.Errorf("Expected to receive error %v, received %v", ErrPartialImport, err)
+	}
+	expected := []ImportResult{
+		{Success: true},
+		{Success: false, Error: "Bad JSON.", Document: "{}"},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, results)
+	}
+}
+
+func TestImportDocuments(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/collections/companies/documents/import" {
+			t.Errorf("Expected to POST to the import endpoint, requested %v %v", req.Method, req.URL.Path)
+		}
+		if action := req.URL.Query().Get("action"); action != "create" {
+			t.Errorf("Expected to import with action %v, imported with %v", "create", action)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		expected := `{"id":"1","name":"Stark Industries"}` + "\n" + `{"id":"2","name":"Acme"}` + "\n"
+		if string(body) != expected {
+			t.Errorf("Expected to import %q, imported %q", expected, string(body))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"success": true}
+{"success": false, "error": "A document with id 2 already exists.", "document": "{\"id\":\"2\",\"name\":\"Acme\"}"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documents := []interface{}{
+		map[string]string{"id": "1", "name": "Stark Industries"},
+		map[string]string{"id": "2", "name": "Acme"},
+	}
+	results, err := client.ImportDocuments("companies", documents, "create")
+	if !errors.Is(err, ErrPartialImport) {
+		t.Errorf("Expected to receive error %v, received %v", ErrPartialImport, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("Expected the error to count the failed documents, received %v", err)
+	}
+	expected := []ImportResult{
+		{Success: true},
+		{Success: false, Error: "A document with id 2 already exists.", Document: `{"id":"2","name":"Acme"}`},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, results)
+	}
+	if failed := FailedImports(results); !reflect.DeepEqual(failed, expected[1:]) {
+		t.Errorf("Expected to receive the failed imports %v, received %v", expected[1:], failed)
+	}
+}
+
+func TestImportResult_DocumentError(t *testing.T) {
+	if err := (ImportResult{Success: true}).DocumentError(); err != nil {
+		t.Errorf("Expected no error for an imported document, received %v", err)
+	}
+	result := ImportResult{Error: "Field `year` must be an int32.", Document: `{"id":"1","year":"1984"}`}
+	expected := &DocumentError{Message: result.Error, Field: "year", Document: result.Document}
+	if err := result.DocumentError(); !reflect.DeepEqual(err, expected) {
+		t.Errorf("Expected to receive error %+v, received %+v", expected, err)
+	}
+	if err := result.DocumentError(); errors.Unwrap(err) != nil {
+		t.Errorf("Expected the error to wrap no error, received %v", errors.Unwrap(err))
+	}
+}
+
+func TestImportDocuments_invalidAction(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Expected to not make any request")
+		return nil, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.ImportDocuments("companies", []interface{}{}, "replace"); err != ErrInvalidImportAction {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidImportAction, err)
+	}
+}
+
+func TestImportDocumentsReader(t *testing.T) {
+	jsonl := `{"id":"1","name":"Stark Industries"}` + "\n" + `{"id":"2","name":"Acme"}` + "\n"
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if action := req.URL.Query().Get("action"); action != "upsert" {
+			t.Errorf("Expected to import with action %v, imported with %v", "upsert", action)
+		}
+		if encoding := req.Header.Get("Content-Encoding"); encoding != "" {
+			t.Errorf("Expected the streamed body to not be compressed, received Content-Encoding %q", encoding)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != jsonl {
+			t.Errorf("Expected to import %q, imported %q", jsonl, string(body))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}` + "\n" + `{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient:         mockClient,
+		masterNode:         testMasterNode,
+		requestCompression: true,
+	}
+	results, err := client.ImportDocumentsReader("companies", strings.NewReader(jsonl), "upsert")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []ImportResult{{Success: true}, {Success: true}}; !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected to receive %v, received %v", expected, results)
+	}
+	if _, err := client.ImportDocumentsReader("companies", strings.NewReader(jsonl), "insert"); err != ErrInvalidImportAction {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidImportAction, err)
+	}
+}
+
+func TestImportDocumentsProgress(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		lines := strings.Count(string(body), "\n")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(strings.Repeat(`{"success": true}`+"\n", lines))),
+		}, nil
+	}
+	client := Client{
+		httpClient:      mockClient,
+		masterNode:      testMasterNode,
+		importBatchSize: 2,
+	}
+	documents := []interface{}{
+		testCompany{ID: "1", CompanyName: "Stark Industries"},
+		testCompany{ID: "2", CompanyName: "Acme"},
+		testCompany{ID: "3", CompanyName: "Wayne Enterprises"},
+		testCompany{ID: "4", CompanyName: "Umbrella"},
+		testCompany{ID: "5", CompanyName: "Cyberdyne"},
+	}
+	var progress []int
+	results, err := client.ImportDocumentsProgress("companies", documents, "create", func(done, total int) {
+		if total != len(documents) {
+			t.Errorf("Expected to receive total %d, received %d", len(documents), total)
+		}
+		progress = append(progress, done)
+	})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if len(results) != len(documents) {
+		t.Errorf("Expected to receive %d results, received %d", len(documents), len(results))
+	}
+	if expected := []int{2, 4, 5}; !reflect.DeepEqual(progress, expected) {
+		t.Errorf("Expected to receive progress %v, received %v", expected, progress)
+	}
+}
+
+func TestImportDocumentsProgress_partial(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		results := `{"success": true}` + "\n"
+		if strings.Contains(string(body), `"id":"1"`) {
+			results = `{"success": false, "error": "Bad JSON.", "document": "{}"}` + "\n"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(results)),
+		}, nil
+	}
+	client := Client{
+		httpClient:      mockClient,
+		masterNode:      testMasterNode,
+		importBatchSize: 1,
+	}
+	documents := []interface{}{
+		testCompany{ID: "1", CompanyName: "Stark Industries"},
+		testCompany{ID: "2", CompanyName: "Acme"},
+	}
+	results, err := client.ImportDocumentsProgress("companies", documents, "create", nil)
+	if !errors.Is(err, ErrPartialImport) {
+		t.Errorf("Expected to receive error %v, received %v", ErrPartialImport, err)
+	}
+	if len(results) != 2 || results[0].Success || !results[1].Success {
+		t.Errorf("Expected to receive the results of both batches, received %v", results)
+	}
+}
+
+func TestImportDocuments_remoteEmbedding(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("action") != "create" {
+			t.Errorf("Expected to import with action %v, imported with %v", "create", query.Get("action"))
+		}
+		if query.Get("remote_embedding_timeout_ms") != "5000" || query.Get("remote_embedding_num_tries") != "3" {
+			t.Errorf("Expected to receive the remote embedding parameters, received %v", query)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documents := []interface{}{testCompany{ID: "1", CompanyName: "Stark Industries"}}
+	_, err := client.ImportDocuments("companies", documents, "create", WithRemoteEmbeddingTimeout(5000), WithRemoteEmbeddingNumTries(3))
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
+
+func TestImportDocuments_dirtyValues(t *testing.T) {
+	var requests int
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requests++
+		if dirtyValues := req.URL.Query().Get("dirty_values"); dirtyValues != "coerce_or_drop" {
+			t.Errorf("Expected to receive dirty_values %q, received %q", "coerce_or_drop", dirtyValues)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	documents := []interface{}{map[string]interface{}{"id": "1", "num_employees": "5215"}}
+	if _, err := client.ImportDocuments("companies", documents, "upsert", WithDirtyValues("coerce_or_drop")); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	_, err := client.ImportDocuments("companies", documents, "upsert", WithDirtyValues("coerce"))
+	if !errors.Is(err, ErrInvalidDirtyValues) {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidDirtyValues, err)
+	}
+	if _, err := client.UpsertDocument("companies", documents[0], WithDirtyValues("coerce")); !errors.Is(err, ErrInvalidDirtyValues) {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidDirtyValues, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected to not make requests with an invalid mode, made %d", requests-1)
+	}
+}
+
+func TestImportDocuments_badRequest(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Parameter ` + "`dirty_values`" + ` is invalid."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	_, err := client.ImportDocuments("companies", []interface{}{map[string]string{"id": "1"}}, "create")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected to receive an *APIError, received %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "Parameter `dirty_values` is invalid." {
+		t.Errorf("Expected to receive the status code and message of the response, received %+v", apiErr)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected the bad request to not match %v", ErrUnauthorized)
+	}
+	if !errors.Is(&APIError{StatusCode: http.StatusUnauthorized}, ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized API error to match %v", ErrUnauthorized)
+	}
+}
+
+func TestImportDocuments_requestCompression(t *testing.T) {
+	var received []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("Expected to receive a gzip body, received %v", err)
+			}
+			body, _ = ioutil.ReadAll(gzipReader)
+			received = append(received, "gzip:"+string(body))
+		} else {
+			received = append(received, string(body))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	WithRequestCompression(20)(&client)
+	if _, err := client.ImportDocuments("companies", []interface{}{map[string]string{"id": "1", "name": "Acme"}}, "create"); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if _, err := client.ImportDocuments("companies", []interface{}{map[string]string{"id": "1"}}, "create"); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := []string{"gzip:" + `{"id":"1","name":"Acme"}` + "\n", `{"id":"1"}` + "\n"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected to receive the bodies %q, received %q", expected, received)
+	}
+}
+
+func TestImportDocumentsStream(t *testing.T) {
+	var batches []int
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		batches = append(batches, len(lines))
+		if len(batches) == 2 {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden"}`)),
+			}, nil
+		}
+		results := strings.Repeat(`{"success": true}`+"\n", len(lines))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(results)),
+		}, nil
+	}
+	client := Client{
+		httpClient:      mockClient,
+		masterNode:      testMasterNode,
+		importBatchSize: 2,
+	}
+	docs := make(chan interface{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			docs <- map[string]interface{}{"id": strconv.Itoa(i)}
+		}
+		close(docs)
+	}()
+	results, err := client.ImportDocumentsStream(context.Background(), collectionNameTest, docs, "upsert")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	var received []ImportResult
+	for result := range results {
+		received = append(received, result)
+	}
+	if expected := []int{2, 2, 1}; !reflect.DeepEqual(batches, expected) {
+		t.Errorf("Expected to import the batches %v, imported %v", expected, batches)
+	}
+	if len(received) != 5 {
+		t.Fatalf("Expected to receive 5 results, received %v", received)
+	}
+	for i, result := range received {
+		if failed := i == 2 || i == 3; result.Success == failed {
+			t.Errorf("Expected the result %d to succeed only outside the failed batch, received %+v", i, result)
+		}
+	}
+	if received[2].Error != ErrUnauthorized.Error() {
+		t.Errorf("Expected the failed results to hold error %v, received %q", ErrUnauthorized, received[2].Error)
+	}
+}
+
+func TestImportDocumentsStream_cancel(t *testing.T) {
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := client.ImportDocumentsStream(ctx, collectionNameTest, make(chan interface{}), "create")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	cancel()
+	if _, ok := <-results; ok {
+		t.Errorf("Expected the results to be closed when the context is cancelled")
+	}
+	if _, err := client.ImportDocumentsStream(ctx, collectionNameTest, nil, "replace"); err != ErrInvalidImportAction {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidImportAction, err)
+	}
+}
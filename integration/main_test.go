@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/GianOrtiz/typesense-go"
 	"github.com/ory/dockertest"
@@ -95,7 +96,7 @@ func TestMain(m *testing.M) {
 		APIKey:   testTypesenseAPIKey,
 	}
 	err = pool.Retry(func() error {
-		testClient = typesense.NewClient(masterNode, 40)
+		testClient = typesense.NewClient(masterNode, typesense.WithTimeout(40*time.Second))
 		return testClient.Ping()
 	})
 	if err != nil {
@@ -158,7 +159,10 @@ func TestRetrieveDocument(t *testing.T) {
 }
 
 func TestSearchDocument(t *testing.T) {
-	searchRes, err := testClient.Search(testCollection.Name, strTestValue, []string{strFieldName}, nil)
+	searchRes, err := testClient.Search(testCollection.Name, typesense.SearchParameters{
+		Q:       strTestValue,
+		QueryBy: []string{strFieldName},
+	})
 	assert.Equal(t, nil, err)
 	if err == nil {
 		assert.Equal(t, 1, searchRes.Found)
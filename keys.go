@@ -0,0 +1,267 @@
+package typesense
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	keysEndpoint = "keys"
+
+	// scopedKeyPrefixLength is the number of characters of the parent
+	// search key embedded in a scoped search key.
+	scopedKeyPrefixLength = 4
+)
+
+// scopedKeyDigestLength is the length of the base64 encoded HMAC-SHA256
+// digest embedded in a scoped search key.
+var scopedKeyDigestLength = base64.StdEncoding.EncodedLen(sha256.Size)
+
+// APIKey is a Typesense API key. The full value of the key is only
+// returned when the key is created, otherwise only its prefix is.
+type APIKey struct {
+	ID          int      `json:"id"`
+	Value       string   `json:"value,omitempty"`
+	ValuePrefix string   `json:"value_prefix,omitempty"`
+	Description string   `json:"description"`
+	Actions     []string `json:"actions"`
+	Collections []string `json:"collections"`
+	ExpiresAt   int64    `json:"expires_at,omitempty"`
+}
+
+// APIKeyDeleteResult is the response of the deletion of an API key.
+type APIKeyDeleteResult struct {
+	ID int `json:"id"`
+}
+
+// APIKeyAudit is the result of the audit of an API key, the findings
+// describe why the key is overly broad, a key without findings passed
+// the audit.
+type APIKeyAudit struct {
+	Key      APIKey
+	Findings []string
+}
+
+// RetrieveAPIKeys retrieves all API keys, it requires an admin key.
+func (c *Client) RetrieveAPIKeys(opts ...CallOption) ([]*APIKey, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		keysEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	type keysResponse struct {
+		Keys []*APIKey `json:"keys"`
+	}
+	var keys keysResponse
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, err
+	}
+	return keys.Keys, nil
+}
+
+// DeleteAPIKey deletes an API key by its ID and returns the ID of the
+// deleted key. It returns ErrAPIKeyNotFound when the key doesn't exist.
+func (c *Client) DeleteAPIKey(id int, opts ...CallOption) (*APIKeyDeleteResult, error) {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%d",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		keysEndpoint,
+		id,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrAPIKeyNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var result APIKeyDeleteResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteAPIKeys deletes the API keys by their IDs and returns the IDs
+// of the deleted keys. A failed deletion doesn't abort the others, the
+// errors are joined into the returned error. The keys that don't exist
+// are considered already deleted and returned as deleted, so deleting
+// the same keys again is safe.
+func (c *Client) DeleteAPIKeys(ids []int, opts ...CallOption) ([]int, error) {
+	var deleted []int
+	var errs []error
+	for _, id := range ids {
+		if _, err := c.DeleteAPIKey(id, opts...); err != nil && err != ErrAPIKeyNotFound {
+			errs = append(errs, fmt.Errorf("key %d: %w", id, err))
+			continue
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// AuditAPIKeys retrieves all API keys and flags the overly broad ones,
+// i.e. the keys allowed to make any action, to access any collection
+// or to manage other API keys. It helps security reviews to find the
+// keys that must be scoped down.
+func (c *Client) AuditAPIKeys(opts ...CallOption) ([]APIKeyAudit, error) {
+	keys, err := c.RetrieveAPIKeys(opts...)
+	if err != nil {
+		return nil, err
+	}
+	audits := make([]APIKeyAudit, len(keys))
+	for i, key := range keys {
+		audits[i] = APIKeyAudit{Key: *key, Findings: auditAPIKey(key)}
+	}
+	return audits, nil
+}
+
+// auditAPIKey returns the findings of the audit of the key.
+func auditAPIKey(key *APIKey) []string {
+	var findings []string
+	for _, action := range key.Actions {
+		if action == "*" {
+			findings = append(findings, `actions include "*", the key is allowed to make any action`)
+		} else if strings.HasPrefix(action, "keys:") {
+			findings = append(findings, fmt.Sprintf("actions include %q, the key is allowed to manage API keys", action))
+		}
+	}
+	for _, collection := range key.Collections {
+		if collection == "*" {
+			findings = append(findings, `collections include "*", the key is allowed to access any collection`)
+		}
+	}
+	return findings
+}
+
+// GenerateScopedSearchKey generates a scoped search key from the parent
+// search key embedding the given search parameters, e.g. a filter_by
+// that can't be overridden by the users of the key. The scoped key is
+// generated locally, without any request to Typesense.
+func GenerateScopedSearchKey(searchKey string, params map[string]string) (string, error) {
+	return generateScopedSearchKey(searchKey, params)
+}
+
+// GenerateScopedSearchKeyWithExpiry generates a scoped search key like
+// GenerateScopedSearchKey that expires at the given Unix timestamp,
+// embedding it as expires_at. The values of the parameters can be of
+// any type that marshals into JSON, e.g. numbers.
+func GenerateScopedSearchKeyWithExpiry(searchKey string, params map[string]interface{}, expiresAt int64) (string, error) {
+	embedded := make(map[string]interface{}, len(params)+1)
+	for name, value := range params {
+		embedded[name] = value
+	}
+	embedded["expires_at"] = expiresAt
+	return generateScopedSearchKey(searchKey, embedded)
+}
+
+// TenantKeySpec is the tenant of a scoped search key generated with
+// GenerateTenantKeys and the filter restricting the searches of the
+// tenant to its documents, e.g. "tenant_id:=acme".
+type TenantKeySpec struct {
+	TenantID string
+	FilterBy string
+}
+
+// GenerateTenantKeys generates a scoped search key for each tenant from
+// the parent search key, embedding the filter of the tenant. It returns
+// the keys by tenant ID. A tenant without a filter returns
+// ErrTenantFilterRequired, its key would search the documents of all
+// the tenants.
+func (c *Client) GenerateTenantKeys(parentKey string, tenants []TenantKeySpec) (map[string]string, error) {
+	keys := make(map[string]string, len(tenants))
+	for _, tenant := range tenants {
+		if tenant.FilterBy == "" {
+			return nil, fmt.Errorf("%w: %s", ErrTenantFilterRequired, tenant.TenantID)
+		}
+		key, err := GenerateScopedSearchKey(parentKey, map[string]string{"filter_by": tenant.FilterBy})
+		if err != nil {
+			return nil, err
+		}
+		keys[tenant.TenantID] = key
+	}
+	return keys, nil
+}
+
+func generateScopedSearchKey(searchKey string, params interface{}) (string, error) {
+	if len(searchKey) < scopedKeyPrefixLength {
+		return "", ErrInvalidSearchKey
+	}
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(searchKey))
+	if _, err := mac.Write(paramsJSON); err != nil {
+		return "", err
+	}
+	digest := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	rawKey := digest + searchKey[:scopedKeyPrefixLength] + string(paramsJSON)
+	return base64.StdEncoding.EncodeToString([]byte(rawKey)), nil
+}
+
+// DecodeScopedKey decodes a scoped search key returning the prefix of
+// the parent search key and the embedded search parameters. It is
+// useful to debug what a distributed scoped key enforces. The HMAC
+// digest of the key can't be verified without the parent search key,
+// so the key is not guaranteed to be accepted by Typesense.
+func DecodeScopedKey(scopedKey string) (parentPrefix string, embedded map[string]interface{}, err error) {
+	rawKey, err := base64.StdEncoding.DecodeString(scopedKey)
+	if err != nil {
+		return "", nil, ErrInvalidScopedKey
+	}
+	if len(rawKey) <= scopedKeyDigestLength+scopedKeyPrefixLength {
+		return "", nil, ErrInvalidScopedKey
+	}
+	parentPrefix = string(rawKey[scopedKeyDigestLength : scopedKeyDigestLength+scopedKeyPrefixLength])
+	if err := json.Unmarshal(rawKey[scopedKeyDigestLength+scopedKeyPrefixLength:], &embedded); err != nil {
+		return "", nil, ErrInvalidScopedKey
+	}
+	return parentPrefix, embedded, nil
+}
+
+// SignRequest signs a request with the secret for proxies in front of
+// Typesense that verify the requests they receive. It returns the hex
+// encoded HMAC-SHA256 of the canonical request, made of the upper case
+// method, the path and the hex encoded SHA-256 of the body, separated
+// by new lines:
+//
+//	POST\n/collections/companies/documents\n<hex sha256 of body>
+//
+// The path must not include the query string, an empty body is hashed
+// like any other body.
+func SignRequest(secret string, method, path string, body []byte) string {
+	bodyDigest := sha256.Sum256(body)
+	canonicalRequest := strings.ToUpper(method) + "\n" + path + "\n" + hex.EncodeToString(bodyDigest[:])
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonicalRequest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,248 @@
+package typesense
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateScopedSearchKey(t *testing.T) {
+	searchKey := "RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127"
+	params := map[string]string{"filter_by": "company_id:124"}
+	scopedKey, err := GenerateScopedSearchKey(searchKey, params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	rawKey, err := base64.StdEncoding.DecodeString(scopedKey)
+	if err != nil {
+		t.Fatalf("Expected the scoped key to be base64 encoded, received %v", err)
+	}
+	paramsJSON := `{"filter_by":"company_id:124"}`
+	mac := hmac.New(sha256.New, []byte(searchKey))
+	mac.Write([]byte(paramsJSON))
+	digest := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if expected := digest + "RN23" + paramsJSON; string(rawKey) != expected {
+		t.Errorf("Expected the scoped key to decode to %s, decoded %s", expected, rawKey)
+	}
+	if _, err := GenerateScopedSearchKey("RN2", params); err != ErrInvalidSearchKey {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidSearchKey, err)
+	}
+}
+
+func TestGenerateScopedSearchKeyWithExpiry(t *testing.T) {
+	params := map[string]interface{}{"filter_by": "company_id:124", "per_page": 20}
+	scopedKey, err := GenerateScopedSearchKeyWithExpiry("RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127", params, 1906054106)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	_, embedded, err := DecodeScopedKey(scopedKey)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected := map[string]interface{}{"filter_by": "company_id:124", "per_page": float64(20), "expires_at": float64(1906054106)}
+	if !reflect.DeepEqual(embedded, expected) {
+		t.Errorf("Expected to receive embedded params %v, received %v", expected, embedded)
+	}
+	if _, ok := params["expires_at"]; ok {
+		t.Errorf("Expected to not modify the params, received %v", params)
+	}
+	if _, err := GenerateScopedSearchKeyWithExpiry("RN2", params, 1906054106); err != ErrInvalidSearchKey {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidSearchKey, err)
+	}
+}
+
+func TestDecodeScopedKey(t *testing.T) {
+	params := map[string]string{
+		"filter_by": "company_id:124",
+	}
+	scopedKey, err := GenerateScopedSearchKey("RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	parentPrefix, embedded, err := DecodeScopedKey(scopedKey)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if parentPrefix != "RN23" {
+		t.Errorf("Expected to receive parent prefix %v, received %v", "RN23", parentPrefix)
+	}
+	expected := map[string]interface{}{"filter_by": "company_id:124"}
+	if !reflect.DeepEqual(embedded, expected) {
+		t.Errorf("Expected to receive embedded params %v, received %v", expected, embedded)
+	}
+}
+
+func TestDecodeScopedKey_invalid(t *testing.T) {
+	for _, scopedKey := range []string{"not base64!", "c2hvcnQ="} {
+		if _, _, err := DecodeScopedKey(scopedKey); err != ErrInvalidScopedKey {
+			t.Errorf("Expected to receive error %v for %q, received %v", ErrInvalidScopedKey, scopedKey, err)
+		}
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		body     []byte
+		expected string
+	}{
+		{"POST", "/collections/companies/documents", []byte(`{"name":"Stark Industries"}`), "734bc060d9fc6ce2eea2cdd1cdf366bd27e02561093cd552742d704d2ed4d311"},
+		{"get", "/health", nil, "8ec71879378ccb771993f54edf7755ca9a385bb71cdf274ba415a2a47faede1b"},
+	}
+	for _, test := range tests {
+		signature := SignRequest("proxy-secret", test.method, test.path, test.body)
+		if signature != test.expected {
+			t.Errorf("Expected to receive signature %s for %s %s, received %s", test.expected, test.method, test.path, signature)
+		}
+	}
+}
+
+func TestAuditAPIKeys(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"keys": [
+				{"id": 1, "value_prefix": "Hu52", "description": "Admin key", "actions": ["*"], "collections": ["*"]},
+				{"id": 2, "value_prefix": "RN23", "description": "Search key", "actions": ["documents:search"], "collections": ["companies"]}
+			]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	audits, err := client.AuditAPIKeys()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(audits) != 2 {
+		t.Fatalf("Expected to receive %d audits, received %d", 2, len(audits))
+	}
+	if audits[0].Key.ID != 1 || len(audits[0].Findings) != 2 {
+		t.Errorf("Expected the admin key to have two findings, received %v", audits[0].Findings)
+	}
+	if audits[1].Key.ID != 2 || len(audits[1].Findings) != 0 {
+		t.Errorf("Expected the search key to pass the audit, received %v", audits[1].Findings)
+	}
+}
+
+func TestAuditAPIKeys_unauthorized(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.AuditAPIKeys(); err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+}
+
+func TestGenerateTenantKeys(t *testing.T) {
+	client := Client{}
+	tenants := []TenantKeySpec{
+		{TenantID: "acme", FilterBy: "tenant_id:=acme"},
+		{TenantID: "globex", FilterBy: "tenant_id:=globex"},
+	}
+	keys, err := client.GenerateTenantKeys("RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127", tenants)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected to receive 2 keys, received %v", len(keys))
+	}
+	for _, tenant := range tenants {
+		_, embedded, err := DecodeScopedKey(keys[tenant.TenantID])
+		if err != nil {
+			t.Fatalf("Expected to receive no errors, received %v", err)
+		}
+		if expected := map[string]interface{}{"filter_by": tenant.FilterBy}; !reflect.DeepEqual(embedded, expected) {
+			t.Errorf("Expected the key of %s to embed %v, embedded %v", tenant.TenantID, expected, embedded)
+		}
+	}
+	_, err = client.GenerateTenantKeys("RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127", []TenantKeySpec{{TenantID: "initech"}})
+	if !errors.Is(err, ErrTenantFilterRequired) {
+		t.Errorf("Expected to receive error %v, received %v", ErrTenantFilterRequired, err)
+	}
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete || req.URL.Path != "/keys/42" {
+			t.Errorf("Expected to request DELETE /keys/42, requested %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": 42}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	result, err := client.DeleteAPIKey(42)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if result.ID != 42 {
+		t.Errorf("Expected to delete the key 42, deleted %d", result.ID)
+	}
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Found"}`)),
+		}, nil
+	}
+	client.httpClient = mockClient
+	if _, err := client.DeleteAPIKey(42); err != ErrAPIKeyNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrAPIKeyNotFound, err)
+	}
+}
+
+func TestDeleteAPIKeys(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		statusCode := http.StatusOK
+		body := `{"id": 1}`
+		switch req.URL.Path {
+		case "/keys/2":
+			statusCode, body = http.StatusNotFound, `{"message": "Not Found"}`
+		case "/keys/3":
+			statusCode, body = http.StatusUnauthorized, `{"message": "Forbidden"}`
+		case "/keys/5":
+			statusCode, body = http.StatusForbidden, `{"message": "Forbidden"}`
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	deleted, err := client.DeleteAPIKeys([]int{1, 2, 3, 4, 5})
+	if expected := []int{1, 2, 4}; !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Expected to delete the keys %v, deleted %v", expected, deleted)
+	}
+	if !errors.Is(err, ErrUnauthorized) || !strings.Contains(err.Error(), "key 3") {
+		t.Errorf("Expected to receive error %v for key 3, received %v", ErrUnauthorized, err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || !strings.Contains(err.Error(), "key 5") {
+		t.Errorf("Expected to receive a forbidden error for key 5, received %v", err)
+	}
+	if _, err := client.DeleteAPIKeys([]int{1}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package typesense
+
+import "time"
+
+// Logger logs what the client does, e.g. the requests it makes to
+// Typesense, see WithLogger. The implementations must be safe for
+// concurrent use.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// WithLogger sets the logger of the client. The requests are logged at
+// debug level with their method, URL, attempt and status code, the
+// transport errors at error level. The API key is redacted to its
+// first characters and the bodies of the requests are never logged. By
+// default the client logs nothing.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// noopLogger is the logger of the clients without one.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Errorf(format string, args ...interface{}) {}
+
+// log returns the logger of the client.
+func (c *Client) log() Logger {
+	if c.logger == nil {
+		return noopLogger{}
+	}
+	return c.logger
+}
+
+// logResponse logs the outcome of a request made by the client.
+func (c *Client) logResponse(info ResponseInfo, apiKey string) {
+	if info.Err != nil {
+		c.log().Errorf(
+			"typesense: %s %s (attempt %d, key %s) failed after %v: %v",
+			info.Method, info.URL, info.Attempt, redactAPIKey(apiKey), info.Duration.Round(time.Millisecond), info.Err,
+		)
+		return
+	}
+	c.log().Debugf(
+		"typesense: %s %s (attempt %d, key %s) returned %d in %v",
+		info.Method, info.URL, info.Attempt, redactAPIKey(apiKey), info.StatusCode, info.Duration.Round(time.Millisecond),
+	)
+}
+
+// redactAPIKey keeps the first characters of the API key, the same
+// prefix a scoped search key embeds of its parent key.
+func redactAPIKey(apiKey string) string {
+	if len(apiKey) <= scopedKeyPrefixLength {
+		return "***"
+	}
+	return apiKey[:scopedKeyPrefixLength] + "***"
+}
@@ -0,0 +1,60 @@
+package typesense
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, "DEBUG "+fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Errorf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, "ERROR "+fmt.Sprintf(format, args...))
+}
+
+func TestWithLogger(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "1"}`)),
+		}, nil
+	}
+	logger := &testLogger{}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	WithLogger(logger)(&client)
+	document := map[string]string{"id": "1", "company_name": "Stark Industries"}
+	if resp := client.IndexDocument(collectionNameTest, document, WithAPIKey("RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127")); resp.Error != nil {
+		t.Fatalf("Expected to receive no errors, received %v", resp.Error)
+	}
+	if len(logger.lines) != 1 {
+		t.Fatalf("Expected to log 1 line, logged %v", logger.lines)
+	}
+	line := logger.lines[0]
+	for _, expected := range []string{"DEBUG", "POST", "/collections/" + collectionNameTest + "/documents", "attempt 1", "RN23***", "201"} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("Expected the log to contain %q, logged %q", expected, line)
+		}
+	}
+	for _, unexpected := range []string{"RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127", "Stark Industries"} {
+		if strings.Contains(line, unexpected) {
+			t.Errorf("Expected the log to not contain %q, logged %q", unexpected, line)
+		}
+	}
+}
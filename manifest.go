@@ -0,0 +1,50 @@
+package typesense
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// SchemaManifest is the configuration of all collections of Typesense,
+// it allows versioning the whole configuration, e.g. in a repository.
+type SchemaManifest struct {
+	Collections []CollectionManifest `json:"collections"`
+}
+
+// CollectionManifest is the configuration of a collection, its schema
+// and its curation rules.
+type CollectionManifest struct {
+	Schema    CollectionSchema `json:"schema"`
+	Overrides []*Override      `json:"overrides"`
+	Synonyms  []*Synonym       `json:"synonyms"`
+}
+
+// ExportAllSchemas exports the configuration of all collections as a
+// JSON encoded SchemaManifest. The collections are sorted by name so
+// the manifest of an unchanged configuration is stable.
+func (c *Client) ExportAllSchemas(opts ...CallOption) ([]byte, error) {
+	collections, err := c.RetrieveCollections(opts...)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		return collections[i].Name < collections[j].Name
+	})
+	manifest := SchemaManifest{Collections: make([]CollectionManifest, len(collections))}
+	for i, collection := range collections {
+		overrides, err := c.RetrieveOverrides(collection.Name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		synonyms, err := c.RetrieveSynonyms(collection.Name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Collections[i] = CollectionManifest{
+			Schema:    collection.Schema(),
+			Overrides: overrides,
+			Synonyms:  synonyms,
+		}
+	}
+	return json.MarshalIndent(manifest, "", "  ")
+}
@@ -0,0 +1,53 @@
+package typesense
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExportAllSchemas(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		body := `[
+			{"name": "products", "fields": [{"name": "title", "type": "string"}], "num_documents": 10},
+			{"name": "companies", "fields": [{"name": "company_name", "type": "string"}], "num_documents": 3}
+		]`
+		if strings.HasSuffix(req.URL.Path, "/overrides") {
+			body = `{"overrides": [{"id": "promote-stark", "rule": {"query": "stark", "match": "exact"}}]}`
+		} else if strings.HasSuffix(req.URL.Path, "/synonyms") {
+			body = `{"synonyms": [{"id": "coat-synonyms", "synonyms": ["blazer", "coat"]}]}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	manifestJSON, err := client.ExportAllSchemas()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	var manifest SchemaManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		t.Fatalf("Expected to decode the manifest, received %v", err)
+	}
+	if len(manifest.Collections) != 2 {
+		t.Fatalf("Expected the manifest to contain %d collections, received %d", 2, len(manifest.Collections))
+	}
+	if manifest.Collections[0].Schema.Name != "companies" || manifest.Collections[1].Schema.Name != "products" {
+		t.Errorf("Expected the collections to be sorted by name, received %s and %s", manifest.Collections[0].Schema.Name, manifest.Collections[1].Schema.Name)
+	}
+	for _, collection := range manifest.Collections {
+		if len(collection.Overrides) != 1 || len(collection.Synonyms) != 1 {
+			t.Errorf("Expected the curation of %s in the manifest, received %+v", collection.Schema.Name, collection)
+		}
+	}
+	if strings.Contains(string(manifestJSON), "num_documents") {
+		t.Errorf("Expected the manifest to contain only the schemas, received %s", manifestJSON)
+	}
+}
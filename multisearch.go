@@ -0,0 +1,178 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const multiSearchEndpoint = "multi_search"
+
+// MultiSearchResponse is the Typesense response for a multi search.
+type MultiSearchResponse struct {
+	// Results are the responses of the searches, in the same order
+	// of the searches. A search that failed has its Error set.
+	Results []SearchResponse `json:"results"`
+}
+
+// MultiSearch makes several searches in a single request. The common
+// parameters are applied to all searches and each search may override
+// them, the Collection of each search or of the common parameters
+// selects the collection to search. A failure of a single search
+// does not fail the whole call, it is reported in the Error of its
+// result.
+func (c *Client) MultiSearch(commonParams SearchParameters, searches []SearchParameters, opts ...CallOption) (*MultiSearchResponse, error) {
+	type multiSearchRequest struct {
+		Searches []map[string]string `json:"searches"`
+	}
+	request := multiSearchRequest{Searches: make([]map[string]string, len(searches))}
+	for i := range searches {
+		request.Searches[i] = searches[i].multiSearchValues()
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	commonParams = c.searchDefaults(commonParams)
+	query := url.Values{}
+	for name, value := range commonParams.multiSearchValues() {
+		query.Set(name, value)
+	}
+	method := http.MethodPost
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s?%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		multiSearchEndpoint,
+		query.Encode(),
+	)
+	resp, err := c.apiCall(method, url, body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var multiSearchResponse MultiSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&multiSearchResponse); err != nil {
+		return nil, err
+	}
+	return &multiSearchResponse, nil
+}
+
+// multiSearchValues returns the parameters that are set, without
+// validating them since the query and the fields to query may be
+// given by the common parameters of the multi search.
+func (opts *SearchParameters) multiSearchValues() map[string]string {
+	data := url.Values{}
+	if opts.Collection != "" {
+		data.Set("collection", opts.Collection)
+	}
+	if opts.Q != "" {
+		data.Set("q", opts.Q)
+	}
+	if len(opts.QueryBy) > 0 {
+		data.Set("query_by", strings.Join(opts.QueryBy, ","))
+	}
+	opts.setOptionalFields(&data)
+	values := make(map[string]string, len(data))
+	for name := range data {
+		values[name] = data.Get(name)
+	}
+	return values
+}
+
+// DocRef references a document of a collection by its id.
+type DocRef struct {
+	Collection string
+	ID         string
+}
+
+// DocResult is the result of retrieving a document with MultiGet, it
+// has either the document or the error that prevented retrieving it,
+// e.g. ErrDocumentNotFound.
+type DocResult struct {
+	Ref      DocRef
+	Document map[string]interface{}
+	Error    error
+}
+
+// maxMultiGetPerPage is the maximum number of documents Typesense
+// returns for a single search.
+const maxMultiGetPerPage = 250
+
+// MultiGet retrieves documents of several collections in a single
+// request, making a multi search that filters the documents of each
+// collection by their ids. The results are in the same order of the
+// references. The ids of a collection are split in searches of at most
+// 250 ids, the maximum number of documents Typesense returns for a
+// search.
+func (c *Client) MultiGet(refs []DocRef, opts ...CallOption) ([]DocResult, error) {
+	var collections []string
+	var ids [][]string
+	// searchOf is the index of the search retrieving each reference and
+	// lastSearch the index of the last search of each collection.
+	searchOf := make(map[DocRef]int, len(refs))
+	lastSearch := make(map[string]int)
+	for _, ref := range refs {
+		if _, ok := searchOf[ref]; ok {
+			continue
+		}
+		i, ok := lastSearch[ref.Collection]
+		if !ok || len(ids[i]) == maxMultiGetPerPage {
+			i = len(collections)
+			collections = append(collections, ref.Collection)
+			ids = append(ids, nil)
+			lastSearch[ref.Collection] = i
+		}
+		ids[i] = append(ids[i], quoteFilterValue(ref.ID))
+		searchOf[ref] = i
+	}
+	searches := make([]SearchParameters, len(collections))
+	for i, collection := range collections {
+		perPage := len(ids[i])
+		searches[i] = SearchParameters{
+			Collection: collection,
+			Q:          wildcardQuery,
+			FilterBy:   []string{fmt.Sprintf("id:=[%s]", strings.Join(ids[i], ", "))},
+			PerPage:    &perPage,
+		}
+	}
+	multiSearchResponse, err := c.MultiSearch(SearchParameters{}, searches, opts...)
+	if err != nil {
+		return nil, err
+	}
+	documents := make(map[DocRef]map[string]interface{})
+	searchErrors := make(map[int]error)
+	for i, result := range multiSearchResponse.Results {
+		if i >= len(collections) {
+			break
+		}
+		if result.Error != "" {
+			searchErrors[i] = &APIError{StatusCode: result.Code, Message: result.Error}
+			continue
+		}
+		for _, hit := range result.Hits {
+			id, _ := hit.Document["id"].(string)
+			documents[DocRef{collections[i], id}] = hit.Document
+		}
+	}
+	results := make([]DocResult, len(refs))
+	for i, ref := range refs {
+		results[i].Ref = ref
+		if err, ok := searchErrors[searchOf[ref]]; ok {
+			results[i].Error = err
+		} else if document, ok := documents[ref]; ok {
+			results[i].Document = document
+		} else {
+			results[i].Error = ErrDocumentNotFound
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,196 @@
+package typesense
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMultiSearch(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/multi_search" {
+			t.Errorf("Expected to request path %s, requested %s", "/multi_search", req.URL.Path)
+		}
+		if req.URL.Query().Get("query_by") != "title" {
+			t.Errorf("Expected to receive common query_by %q, received %q", "title", req.URL.Query().Get("query_by"))
+		}
+		var body struct {
+			Searches []map[string]string `json:"searches"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Expected to decode the request body, received %v", err)
+		}
+		if len(body.Searches) != 2 || body.Searches[1]["collection"] != "authors" {
+			t.Errorf("Expected to receive two searches, received %v", body.Searches)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"results": [` + searchResultTest + `, {"code": 404, "error": "Could not find a collection named authors."}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	searches := []SearchParameters{
+		{Collection: "books", Q: "harry"},
+		{Collection: "authors", Q: "rowling", QueryBy: []string{"name"}},
+	}
+	multiSearchResp, err := client.MultiSearch(SearchParameters{QueryBy: []string{"title"}}, searches)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(multiSearchResp.Results) != 2 {
+		t.Fatalf("Expected to receive %d results, received %d", 2, len(multiSearchResp.Results))
+	}
+	if multiSearchResp.Results[0].Error != "" || len(multiSearchResp.Results[0].Hits) == 0 {
+		t.Errorf("Expected the first search to succeed, received %+v", multiSearchResp.Results[0])
+	}
+	if multiSearchResp.Results[1].Error == "" {
+		t.Errorf("Expected the second search to fail, received %+v", multiSearchResp.Results[1])
+	}
+}
+
+func TestMultiSearch_unauthorized(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Forbidden - a valid x-typesense-api-key header must be sent."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.MultiSearch(SearchParameters{}, []SearchParameters{{Collection: "books", Q: "harry"}}); err != ErrUnauthorized {
+		t.Errorf("Expected to receive error %v, received %v", ErrUnauthorized, err)
+	}
+}
+
+func TestMultiGet(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Searches []map[string]string `json:"searches"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Expected to decode the request body, received %v", err)
+		}
+		expected := []map[string]string{
+			{"collection": "users", "q": "*", "filter_by": "id:=[`1`]", "per_page": "1"},
+			{"collection": "orders", "q": "*", "filter_by": "id:=[`10`, `11`]", "per_page": "2"},
+		}
+		if !reflect.DeepEqual(body.Searches, expected) {
+			t.Errorf("Expected to receive searches %v, received %v", expected, body.Searches)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"results": [
+				{"found": 1, "hits": [{"document": {"id": "1", "name": "Ada"}}]},
+				{"found": 1, "hits": [{"document": {"id": "10", "total": 99}}]}
+			]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	refs := []DocRef{
+		{Collection: "users", ID: "1"},
+		{Collection: "orders", ID: "10"},
+		{Collection: "orders", ID: "11"},
+	}
+	results, err := client.MultiGet(refs)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected to receive 3 results, received %v", results)
+	}
+	if results[0].Ref != refs[0] || results[0].Document["name"] != "Ada" || results[0].Error != nil {
+		t.Errorf("Expected to receive the user 1, received %+v", results[0])
+	}
+	if results[1].Document["total"] != float64(99) || results[1].Error != nil {
+		t.Errorf("Expected to receive the order 10, received %+v", results[1])
+	}
+	if results[2].Document != nil || results[2].Error != ErrDocumentNotFound {
+		t.Errorf("Expected to receive error %v for the order 11, received %+v", ErrDocumentNotFound, results[2])
+	}
+}
+
+func TestMultiGet_chunks(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Searches []map[string]string `json:"searches"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Expected to decode the request body, received %v", err)
+		}
+		if len(body.Searches) != 2 || body.Searches[0]["per_page"] != "250" || body.Searches[1]["per_page"] != "10" {
+			t.Errorf("Expected to receive searches of 250 and 10 ids, received %v", body.Searches)
+		}
+		var results []map[string]interface{}
+		for _, search := range body.Searches {
+			var hits []map[string]interface{}
+			filter := strings.TrimSuffix(strings.TrimPrefix(search["filter_by"], "id:=["), "]")
+			for _, id := range strings.Split(filter, ", ") {
+				hits = append(hits, map[string]interface{}{
+					"document": map[string]interface{}{"id": strings.Trim(id, "`")},
+				})
+			}
+			results = append(results, map[string]interface{}{"found": len(hits), "hits": hits})
+		}
+		response, _ := json.Marshal(map[string]interface{}{"results": results})
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(response)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	refs := make([]DocRef, 260)
+	for i := range refs {
+		refs[i] = DocRef{Collection: "users", ID: strconv.Itoa(i)}
+	}
+	results, err := client.MultiGet(refs)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	for i, result := range results {
+		if result.Error != nil || result.Document["id"] != refs[i].ID {
+			t.Errorf("Expected to receive the user %v, received %+v", refs[i].ID, result)
+		}
+	}
+}
+
+func TestMultiGet_searchError(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"results": [
+				{"code": 404, "error": "Could not find a collection named users."}
+			]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	results, err := client.MultiGet([]DocRef{{Collection: "users", ID: "1"}})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(results[0].Error, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !errors.Is(results[0].Error, ErrNotFound) {
+		t.Errorf("Expected to receive an *APIError with status %d, received %v", http.StatusNotFound, results[0].Error)
+	}
+}
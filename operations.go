@@ -0,0 +1,52 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const operationsEndpoint = "operations"
+
+// operationResponse is the response of the operations endpoints.
+type operationResponse struct {
+	Success bool `json:"success"`
+}
+
+// Snapshot creates a snapshot of the data of the node in the
+// snapshotPath directory of the node, e.g. for backups. It returns
+// ErrSnapshotInProgress when another snapshot is being created, the
+// call can be retried later.
+func (c *Client) Snapshot(snapshotPath string, opts ...CallOption) (bool, error) {
+	if snapshotPath == "" {
+		return false, ErrSnapshotPathRequired
+	}
+	query := url.Values{"snapshot_path": []string{snapshotPath}}
+	method := http.MethodPost
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/snapshot?%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		operationsEndpoint,
+		query.Encode(),
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return false, ErrSnapshotInProgress
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return false, ErrUnauthorized
+	} else if resp.StatusCode == http.StatusBadRequest || resp.StatusCode >= http.StatusInternalServerError {
+		return false, newAPIError(resp)
+	}
+	var operation operationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&operation); err != nil {
+		return false, err
+	}
+	return operation.Success, nil
+}
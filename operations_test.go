@@ -0,0 +1,53 @@
+package typesense
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/operations/snapshot" {
+			t.Errorf("Expected to POST to /operations/snapshot, requested %v %v", req.Method, req.URL.Path)
+		}
+		if snapshotPath := req.URL.Query().Get("snapshot_path"); snapshotPath != "/tmp/typesense-data-snapshot" {
+			t.Errorf("Expected to receive snapshot path %q, received %q", "/tmp/typesense-data-snapshot", snapshotPath)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	success, err := client.Snapshot("/tmp/typesense-data-snapshot")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if !success {
+		t.Errorf("Expected the snapshot to succeed")
+	}
+	if _, err := client.Snapshot(""); err != ErrSnapshotPathRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrSnapshotPathRequired, err)
+	}
+}
+
+func TestSnapshot_inProgress(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusConflict,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Another snapshot is in progress."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.Snapshot("/tmp/typesense-data-snapshot"); err != ErrSnapshotInProgress {
+		t.Errorf("Expected to receive error %v, received %v", ErrSnapshotInProgress, err)
+	}
+}
@@ -0,0 +1,254 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const overridesEndpoint = "overrides"
+
+// Override is a Typesense curation rule to include or exclude
+// documents from the search results of matching queries.
+type Override struct {
+	ID       string            `json:"id"`
+	Rule     OverrideRule      `json:"rule"`
+	Includes []OverrideInclude `json:"includes,omitempty"`
+	Excludes []OverrideExclude `json:"excludes,omitempty"`
+
+	// FilterBy is a filter applied to the search results of the
+	// matching queries.
+	FilterBy string `json:"filter_by,omitempty"`
+
+	// SortBy is the sorting of the search results of the matching
+	// queries.
+	SortBy string `json:"sort_by,omitempty"`
+
+	// ReplaceQuery replaces the matching queries with this query.
+	ReplaceQuery string `json:"replace_query,omitempty"`
+
+	// FilterCuratedHits whether the filters of the search also apply
+	// to the included documents.
+	FilterCuratedHits bool `json:"filter_curated_hits,omitempty"`
+
+	// RemoveMatchedTokens whether the tokens matching the rule are
+	// removed from the query. Default value is true.
+	RemoveMatchedTokens *bool `json:"remove_matched_tokens,omitempty"`
+
+	// StopProcessing whether the processing of the other overrides
+	// stops when this one matches. Default value is true.
+	StopProcessing *bool `json:"stop_processing,omitempty"`
+
+	// EffectiveFromTs and EffectiveToTs are the Unix timestamps, in
+	// seconds, of the window the override is effective in, e.g. for a
+	// time-boxed campaign. The window is open when they are zero.
+	EffectiveFromTs int64 `json:"effective_from_ts,omitempty"`
+	EffectiveToTs   int64 `json:"effective_to_ts,omitempty"`
+}
+
+// OverrideRule is the rule that triggers an override, the query with
+// its match, either exact or contains, and the filter of the search
+// must all match.
+type OverrideRule struct {
+	Query    string `json:"query,omitempty"`
+	Match    string `json:"match,omitempty"`
+	FilterBy string `json:"filter_by,omitempty"`
+}
+
+// OverrideInclude is a document to include at a specific position
+// of the search results.
+type OverrideInclude struct {
+	ID       string `json:"id"`
+	Position int    `json:"position"`
+}
+
+// OverrideExclude is a document to exclude from the search results.
+type OverrideExclude struct {
+	ID string `json:"id"`
+}
+
+// OverrideCollection creates the override of the collection or
+// replaces it if an override with the same id already exists. It
+// returns the override as stored by Typesense.
+func (c *Client) OverrideCollection(collectionName string, override Override, opts ...CallOption) (*Override, error) {
+	method := http.MethodPut
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		overridesEndpoint,
+		override.ID,
+	)
+	overrideJSON, _ := json.Marshal(override)
+	resp, err := c.apiCall(method, url, overrideJSON, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var stored Override
+	if err := json.NewDecoder(resp.Body).Decode(&stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// UpsertOverrides upserts each of the overrides of the collection
+// concurrently, Typesense has no bulk endpoint for overrides, with at
+// most WithMaxConcurrentRequests requests in flight. The returned
+// overrides are the stored ones, aligned with the given overrides, an
+// override that failed is nil without aborting the upsert of the
+// others. The errors of the failed overrides are joined into the
+// returned error.
+func (c *Client) UpsertOverrides(collectionName string, overrides []Override, opts ...CallOption) ([]*Override, error) {
+	upserted := make([]*Override, len(overrides))
+	errs := make([]error, len(overrides))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.maxConcurrentRequests())
+	for i := range overrides {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			override, err := c.OverrideCollection(collectionName, overrides[i], opts...)
+			if err != nil {
+				errs[i] = fmt.Errorf("override %s: %w", overrides[i].ID, err)
+				return
+			}
+			upserted[i] = override
+		}(i)
+	}
+	wg.Wait()
+	return upserted, errors.Join(errs...)
+}
+
+// RetrieveOverride retrieves a single override of the collection by
+// its id.
+func (c *Client) RetrieveOverride(collectionName, overrideID string, opts ...CallOption) (*Override, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		overridesEndpoint,
+		overrideID,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resourceNotFoundError(resp.Body, ErrOverrideNotFound)
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var override Override
+	if err := json.NewDecoder(resp.Body).Decode(&override); err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// RetrieveOverrides retrieves all overrides of the collection.
+func (c *Client) RetrieveOverrides(collectionName string, opts ...CallOption) ([]*Override, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		overridesEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	type overridesResponse struct {
+		Overrides []*Override `json:"overrides"`
+	}
+	var overrides overridesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&overrides); err != nil {
+		return nil, err
+	}
+	return overrides.Overrides, nil
+}
+
+// DeleteOverride deletes an override of the collection by its id.
+func (c *Client) DeleteOverride(collectionName, overrideID string, opts ...CallOption) error {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		overridesEndpoint,
+		overrideID,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+	return nil
+}
+
+// DeleteAllOverrides deletes every override of the collection. It
+// returns the ids of the deleted overrides, the overrides that could
+// not be deleted are reported together in the returned error.
+func (c *Client) DeleteAllOverrides(collectionName string, opts ...CallOption) ([]string, error) {
+	overrides, err := c.RetrieveOverrides(collectionName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make([]string, 0, len(overrides))
+	var failures []string
+	for _, override := range overrides {
+		if err := c.DeleteOverride(collectionName, override.ID, opts...); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", override.ID, err))
+			continue
+		}
+		deleted = append(deleted, override.ID)
+	}
+	if len(failures) > 0 {
+		return deleted, fmt.Errorf("couldn't delete overrides: %s", strings.Join(failures, "; "))
+	}
+	return deleted, nil
+}
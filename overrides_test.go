@@ -0,0 +1,254 @@
+package typesense
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeleteAllOverrides(t *testing.T) {
+	var deletedPaths []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{"overrides": [
+					{"id": "promote-stark", "rule": {"query": "stark", "match": "exact"}, "includes": [{"id": "1", "position": 1}]},
+					{"id": "hide-acme", "rule": {"query": "acme", "match": "contains"}, "excludes": [{"id": "2"}]}
+				]}`)),
+			}, nil
+		}
+		deletedPaths = append(deletedPaths, req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "deleted"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	deleted, err := client.DeleteAllOverrides("companies")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if expected := []string{"promote-stark", "hide-acme"}; !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Expected to delete %v, deleted %v", expected, deleted)
+	}
+	expectedPaths := []string{
+		"/collections/companies/overrides/promote-stark",
+		"/collections/companies/overrides/hide-acme",
+	}
+	if !reflect.DeepEqual(deletedPaths, expectedPaths) {
+		t.Errorf("Expected to request %v, requested %v", expectedPaths, deletedPaths)
+	}
+}
+
+func TestDeleteAllOverrides_collectionNotFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Collection not found"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.DeleteAllOverrides("companies"); err != ErrCollectionNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrCollectionNotFound, err)
+	}
+}
+
+func TestOverrideCollection(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut || req.URL.Path != "/collections/companies/overrides/promote-stark" {
+			t.Errorf("Expected to receive PUT /collections/companies/overrides/promote-stark, received %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"id": "promote-stark",
+				"rule": {"query": "stark", "match": "exact"},
+				"includes": [{"id": "1", "position": 1}],
+				"remove_matched_tokens": true,
+				"stop_processing": true
+			}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	override := Override{ID: "promote-stark", Rule: OverrideRule{Query: "stark", Match: "exact"}, Includes: []OverrideInclude{{ID: "1", Position: 1}}}
+	stored, err := client.OverrideCollection("companies", override)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if stored.RemoveMatchedTokens == nil || !*stored.RemoveMatchedTokens || stored.StopProcessing == nil || !*stored.StopProcessing {
+		t.Errorf("Expected to receive the defaulted fields of the stored override, received %+v", stored)
+	}
+	if !reflect.DeepEqual(stored.Rule, override.Rule) || !reflect.DeepEqual(stored.Includes, override.Includes) {
+		t.Errorf("Expected to receive the stored override %+v, received %+v", override, stored)
+	}
+}
+
+func TestUpsertOverrides(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodPut, req.Method)
+		}
+		if strings.HasSuffix(req.URL.Path, "/invalid-rule") {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "The ` + "`rule`" + ` definition must contain a ` + "`match`" + ` key."}`)),
+			}, nil
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(string(body))),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	overrides := []Override{
+		{ID: "promote-stark", Rule: OverrideRule{Query: "stark", Match: "exact"}, Includes: []OverrideInclude{{ID: "1", Position: 1}}},
+		{ID: "invalid-rule", Rule: OverrideRule{Query: "acme"}},
+		{ID: "hide-acme", Rule: OverrideRule{Query: "acme", Match: "contains"}, Excludes: []OverrideExclude{{ID: "2"}}},
+	}
+	upserted, err := client.UpsertOverrides("companies", overrides)
+	if len(upserted) != 3 {
+		t.Fatalf("Expected to receive 3 results, received %d", len(upserted))
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(err.Error(), "override invalid-rule") {
+		t.Errorf("Expected to receive the error of the invalid override, received %v", err)
+	}
+	if strings.Contains(err.Error(), "promote-stark") || strings.Contains(err.Error(), "hide-acme") {
+		t.Errorf("Expected to upsert the valid overrides, received %v", err)
+	}
+	if upserted[1] != nil {
+		t.Errorf("Expected the invalid override to fail, received %v", upserted[1])
+	}
+	if upserted[0].ID != "promote-stark" || upserted[2].ID != "hide-acme" {
+		t.Errorf("Expected to receive the upserted overrides, received %v and %v", upserted[0], upserted[2])
+	}
+}
+
+func TestUpsertOverrides_maxConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		body, _ := ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	WithMaxConcurrentRequests(2)(&client)
+	overrides := make([]Override, 8)
+	for i := range overrides {
+		overrides[i] = Override{ID: strconv.Itoa(i), Rule: OverrideRule{Query: "stark", Match: "exact"}}
+	}
+	if _, err := client.UpsertOverrides("companies", overrides); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests in flight, received %d", maxInFlight)
+	}
+}
+
+func TestOverride_marshal(t *testing.T) {
+	stopProcessing := false
+	override := Override{
+		ID:                "black-friday",
+		Rule:              OverrideRule{FilterBy: "category:=shoes"},
+		SortBy:            "discount:desc",
+		FilterCuratedHits: true,
+		StopProcessing:    &stopProcessing,
+		EffectiveFromTs:   1700784000,
+		EffectiveToTs:     1701043200,
+	}
+	overrideJSON, err := json.Marshal(override)
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := `{"id":"black-friday","rule":{"filter_by":"category:=shoes"},"sort_by":"discount:desc",` +
+		`"filter_curated_hits":true,"stop_processing":false,"effective_from_ts":1700784000,"effective_to_ts":1701043200}`
+	if string(overrideJSON) != expected {
+		t.Errorf("Expected to marshal %s, marshalled %s", expected, overrideJSON)
+	}
+}
+
+func TestRetrieveOverride(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/collections/companies/overrides/promote-stark" {
+			t.Errorf("Expected to request path %s, requested %s", "/collections/companies/overrides/promote-stark", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "promote-stark", "rule": {"query": "stark", "match": "exact"}, "includes": [{"id": "1", "position": 1}]}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	override, err := client.RetrieveOverride("companies", "promote-stark")
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if override.ID != "promote-stark" || override.Rule.Query != "stark" || len(override.Includes) != 1 {
+		t.Errorf("Expected to receive the promote-stark override, received %+v", override)
+	}
+}
+
+func TestRetrieveOverride_notFound(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected error
+	}{
+		{"Could not find that `id`.", ErrOverrideNotFound},
+		{"Collection not found", ErrCollectionNotFound},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "` + test.message + `"}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		if _, err := client.RetrieveOverride("companies", "promote-stark"); err != test.expected {
+			t.Errorf("Expected to receive error %v, received %v", test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const presetsEndpoint = "presets"
+
+// Preset is a named set of search parameters stored in Typesense, a
+// search references it by name with the Preset of its parameters. It
+// allows tuning the relevance of the searches without a deploy.
+type Preset struct {
+	Name  string
+	Value SearchParameters
+}
+
+type presetJSON struct {
+	Name  string                 `json:"name,omitempty"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// MarshalJSON encodes the preset with the search parameters that are
+// set, named as the parameters of the search endpoint.
+func (p Preset) MarshalJSON() ([]byte, error) {
+	value := make(map[string]interface{})
+	for name, param := range p.Value.multiSearchValues() {
+		value[name] = param
+	}
+	return json.Marshal(presetJSON{Name: p.Name, Value: value})
+}
+
+// UnmarshalJSON decodes the preset, the parameters of the preset that
+// have no field in SearchParameters are ignored, as are the presets of
+// multi searches.
+func (p *Preset) UnmarshalJSON(data []byte) error {
+	var preset presetJSON
+	if err := json.Unmarshal(data, &preset); err != nil {
+		return err
+	}
+	values := make(map[string]string, len(preset.Value))
+	for name, value := range preset.Value {
+		switch v := value.(type) {
+		case string:
+			values[name] = v
+		case bool:
+			values[name] = strconv.FormatBool(v)
+		case float64:
+			values[name] = formatNumber(v)
+		}
+	}
+	p.Name = preset.Name
+	p.Value = searchParametersFromValues(values)
+	return nil
+}
+
+// UpsertPreset creates the preset or replaces its parameters.
+func (c *Client) UpsertPreset(name string, params SearchParameters, opts ...CallOption) (*Preset, error) {
+	method := http.MethodPut
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		presetsEndpoint,
+		name,
+	)
+	presetJSON, err := json.Marshal(Preset{Value: params})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.apiCall(method, url, presetJSON, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var preset Preset
+	if err := json.NewDecoder(resp.Body).Decode(&preset); err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// RetrievePreset retrieves a single preset by its name.
+func (c *Client) RetrievePreset(name string, opts ...CallOption) (*Preset, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		presetsEndpoint,
+		name,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrPresetNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var preset Preset
+	if err := json.NewDecoder(resp.Body).Decode(&preset); err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// RetrievePresets retrieves all the presets.
+func (c *Client) RetrievePresets(opts ...CallOption) ([]*Preset, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		presetsEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	type presetsResponse struct {
+		Presets []*Preset `json:"presets"`
+	}
+	var presets presetsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&presets); err != nil {
+		return nil, err
+	}
+	return presets.Presets, nil
+}
+
+// DeletePreset deletes a preset by its name.
+func (c *Client) DeletePreset(name string, opts ...CallOption) error {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		presetsEndpoint,
+		name,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrPresetNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+	return nil
+}
+
+// searchParametersFromValues decodes the search parameters from their
+// values as sent to the search endpoint, the reverse of
+// setOptionalFields. Malformed numbers and booleans are ignored.
+func searchParametersFromValues(values map[string]string) SearchParameters {
+	list := func(name, separator string) []string {
+		if values[name] == "" {
+			return nil
+		}
+		return strings.Split(values[name], separator)
+	}
+	intValue := func(name string) *int {
+		if n, err := strconv.Atoi(values[name]); err == nil {
+			return &n
+		}
+		return nil
+	}
+	boolValue := func(name string) *bool {
+		if b, err := strconv.ParseBool(values[name]); err == nil {
+			return &b
+		}
+		return nil
+	}
+	intList := func(name string) []int {
+		var ints []int
+		for _, value := range list(name, ",") {
+			if n, err := strconv.Atoi(value); err == nil {
+				ints = append(ints, n)
+			}
+		}
+		return ints
+	}
+	var prefixByField []bool
+	if strings.Contains(values["prefix"], ",") {
+		for _, value := range list("prefix", ",") {
+			b, _ := strconv.ParseBool(value)
+			prefixByField = append(prefixByField, b)
+		}
+	}
+	cacheTTL, _ := strconv.Atoi(values["cache_ttl"])
+	limitHits, _ := strconv.Atoi(values["limit_hits"])
+	maxCandidates, _ := strconv.Atoi(values["max_candidates"])
+	var facetQuery *string
+	if value, ok := values["facet_query"]; ok {
+		facetQuery = &value
+	}
+	return SearchParameters{
+		Q:                             values["q"],
+		QueryBy:                       list("query_by", ","),
+		MaxHits:                       intValue("max_hits"),
+		Prefix:                        boolValue("prefix"),
+		PrefixByField:                 prefixByField,
+		QueryByWeights:                intList("query_by_weights"),
+		FilterBy:                      list("filter_by", " && "),
+		SortBy:                        list("sort_by", ","),
+		FacetBy:                       list("facet_by", ","),
+		MaxFacetValues:                intValue("max_facet_values"),
+		FacetQuery:                    facetQuery,
+		NumTypos:                      intValue("num_typos"),
+		Page:                          intValue("page"),
+		PerPage:                       intValue("per_page"),
+		GroupBy:                       list("group_by", ","),
+		GroupLimit:                    intValue("group_limit"),
+		IncludeFields:                 list("include_fields", ","),
+		ExcludeFields:                 list("exclude_fields", ","),
+		HighlightFullFields:           list("highlight_full_fields", ","),
+		HighlightFields:               list("highlight_fields", ","),
+		HighlightStartTag:             values["highlight_start_tag"],
+		HighlightEndTag:               values["highlight_end_tag"],
+		HighlightAffixNumTokens:       intValue("highlight_affix_num_tokens"),
+		SnippetThreshold:              intValue("snippet_threshold"),
+		DropTokensThreshold:           intValue("drop_tokens_threshold"),
+		TypoTokensThreshold:           intValue("typo_tokens_threshold"),
+		PinnedHits:                    list("pinned_hits", ","),
+		Hiddenhits:                    list("hidden_hits", ","),
+		VectorQuery:                   values["vector_query"],
+		UseCache:                      values["use_cache"] == "true",
+		CacheTTL:                      cacheTTL,
+		DropTokensMode:                values["drop_tokens_mode"],
+		EnableTyposForNumericalTokens: boolValue("enable_typos_for_numerical_tokens"),
+		EnableSynonyms:                boolValue("enable_synonyms"),
+		Collection:                    values["collection"],
+		Conversation:                  values["conversation"] == "true",
+		ConversationModelID:           values["conversation_model_id"],
+		ConversationID:                values["conversation_id"],
+		EnableHighlightV1:             boolValue("enable_highlight_v1"),
+		MaxExtraPrefix:                intValue("max_extra_prefix"),
+		MaxExtraSuffix:                intValue("max_extra_suffix"),
+		SplitJoinTokens:               values["split_join_tokens"],
+		Preset:                        values["preset"],
+		FacetStrategy:                 values["facet_strategy"],
+		EnableAnalytics:               boolValue("enable_analytics"),
+		ExhaustiveSearch:              boolValue("exhaustive_search"),
+		MinLen1Typo:                   intValue("min_len_1typo"),
+		MinLen2Typo:                   intValue("min_len_2typo"),
+		Infix:                         list("infix", ","),
+		PrioritizeExactMatch:          boolValue("prioritize_exact_match"),
+		PrioritizeTokenPosition:       boolValue("prioritize_token_position"),
+		TextMatchType:                 values["text_match_type"],
+		LimitHits:                     limitHits,
+		MaxCandidates:                 maxCandidates,
+		FilterCuratedHits:             boolValue("filter_curated_hits"),
+		EnableOverrides:               boolValue("enable_overrides"),
+		SynonymPrefix:                 boolValue("synonym_prefix"),
+	}
+}
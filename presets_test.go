@@ -0,0 +1,132 @@
+package typesense
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpsertPreset(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut || req.URL.Path != "/presets/listing_view" {
+			t.Errorf("Expected to PUT to /presets/listing_view, requested %v %v", req.Method, req.URL.Path)
+		}
+		var body map[string]map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Expected to decode the request body, received %v", err)
+		}
+		expected := map[string]string{"query_by": "title,description", "sort_by": "popularity:desc", "per_page": "20"}
+		if !reflect.DeepEqual(body["value"], expected) {
+			t.Errorf("Expected to receive the preset value %v, received %v", expected, body["value"])
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"name": "listing_view", "value": {"query_by": "title,description", "sort_by": "popularity:desc", "per_page": 20}}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	perPage := 20
+	params := SearchParameters{
+		QueryBy: []string{"title", "description"},
+		SortBy:  []string{"popularity:desc"},
+		PerPage: &perPage,
+	}
+	preset, err := client.UpsertPreset("listing_view", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected := &Preset{Name: "listing_view", Value: params}
+	if !reflect.DeepEqual(preset, expected) {
+		t.Errorf("Expected to receive preset %+v, received %+v", expected, preset)
+	}
+}
+
+func TestRetrievePreset_notFound(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not found."}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.RetrievePreset("listing_view"); err != ErrPresetNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrPresetNotFound, err)
+	}
+	if err := client.DeletePreset("listing_view"); err != ErrPresetNotFound {
+		t.Errorf("Expected to receive error %v, received %v", ErrPresetNotFound, err)
+	}
+}
+
+func TestRetrievePresets(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"presets": [{"name": "listing_view", "value": {"query_by": "title", "prefix": false}}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	presets, err := client.RetrievePresets()
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if len(presets) != 1 || presets[0].Name != "listing_view" || !reflect.DeepEqual(presets[0].Value.QueryBy, []string{"title"}) {
+		t.Fatalf("Expected to receive the listing_view preset, received %+v", presets)
+	}
+	if prefix := presets[0].Value.Prefix; prefix == nil || *prefix {
+		t.Errorf("Expected to receive prefix false, received %v", prefix)
+	}
+}
+
+func TestPreset_roundTrip(t *testing.T) {
+	enableTypos := false
+	preset := Preset{Value: SearchParameters{
+		QueryBy:                       []string{"part_number"},
+		EnableTyposForNumericalTokens: &enableTypos,
+	}}
+	presetJSON, err := json.Marshal(preset)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	var decoded Preset
+	if err := json.Unmarshal(presetJSON, &decoded); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Value, preset.Value) {
+		t.Errorf("Expected to decode the parameters %+v, decoded %+v", preset.Value, decoded.Value)
+	}
+}
+
+func TestSearch_preset(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if preset := req.URL.Query().Get("preset"); preset != "listing_view" {
+			t.Errorf("Expected to receive preset %q, received %q", "listing_view", preset)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	if _, err := client.Search("books", SearchParameters{Q: "harry", Preset: "listing_view"}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Reindex migrates the documents of a collection into a new collection
+// with the new schema, for the changes Typesense can't make in place,
+// e.g. changing the type of a field. It creates the new collection,
+// exports the documents of the collection, transforms each one with
+// transform and imports them into the new collection, then points the
+// alias with the name of the collection to the new collection.
+//
+// The new collection is named after the new schema, or after the
+// collection with a timestamp suffix when the schema has no name or the
+// same name. When collectionName is an alias, the collection it pointed
+// to is kept and can be dropped by the caller. When it is a collection,
+// it is deleted right before the alias is created, since an alias can't
+// take the name of an existing collection.
+//
+// The numbers of the documents passed to transform are json.Number, to
+// keep the precision of int64 fields. Returning nil from transform
+// skips the document.
+//
+// When the creation, the copy of the documents or the swap of the alias
+// fails, the new collection is deleted and the error is returned,
+// joined with the error of the deletion if it failed too, so Reindex
+// can be retried with the same schema. The one exception is when
+// collectionName is a collection that was already deleted and the alias
+// can't be created: the new collection holds the only copy of the
+// documents, so it is kept and the returned error names it.
+func (c *Client) Reindex(collectionName string, newSchema CollectionSchema, transform func(map[string]interface{}) map[string]interface{}, opts ...CallOption) error {
+	if newSchema.Name == "" || newSchema.Name == collectionName {
+		newSchema.Name = fmt.Sprintf("%s_%d", collectionName, time.Now().Unix())
+	}
+	_, err := c.RetrieveAlias(collectionName, opts...)
+	if err != nil && err != ErrAliasNotFound {
+		return err
+	}
+	isAlias := err == nil
+	if _, err := c.CreateCollection(newSchema, opts...); err != nil {
+		return err
+	}
+	if err := c.copyDocuments(collectionName, newSchema.Name, transform, opts...); err != nil {
+		return c.dropCollectionOnFailure(newSchema.Name, err, opts...)
+	}
+	if !isAlias {
+		if _, err := c.DeleteCollection(collectionName, opts...); err != nil {
+			return c.dropCollectionOnFailure(newSchema.Name, err, opts...)
+		}
+		if _, err = c.UpsertAlias(collectionName, newSchema.Name, opts...); err != nil {
+			return fmt.Errorf("collection %s was deleted but the alias couldn't point to %s, which holds the documents: %w", collectionName, newSchema.Name, err)
+		}
+		return nil
+	}
+	if _, err = c.UpsertAlias(collectionName, newSchema.Name, opts...); err != nil {
+		return c.dropCollectionOnFailure(newSchema.Name, err, opts...)
+	}
+	return nil
+}
+
+// dropCollectionOnFailure deletes the collection built by a failed
+// reindex and returns the error of the reindex, joined with the error
+// of the deletion if it failed too. The deletion is made even when the
+// context of the call is cancelled.
+func (c *Client) dropCollectionOnFailure(collectionName string, err error, opts ...CallOption) error {
+	opts = append(opts[:len(opts):len(opts)], WithContext(context.Background()))
+	if _, deleteErr := c.DeleteCollection(collectionName, opts...); deleteErr != nil {
+		return errors.Join(err, fmt.Errorf("couldn't delete collection %s: %w", collectionName, deleteErr))
+	}
+	return err
+}
+
+// copyDocuments exports the documents of the source collection and
+// imports them, transformed, into the target collection in batches.
+func (c *Client) copyDocuments(source, target string, transform func(map[string]interface{}) map[string]interface{}, opts ...CallOption) error {
+	export, err := c.ExportDocuments(source, opts...)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+	batchSize := c.importBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	batch := make([]map[string]interface{}, 0, batchSize)
+	importBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		body, err := encodeJSONL(c, batch)
+		if err != nil {
+			return err
+		}
+		results, err := c.importJSONL(target, "create", body, opts...)
+		if err != nil {
+			return err
+		}
+		if failed := FailedImports(results); len(failed) > 0 {
+			return fmt.Errorf("%w: %d of %d documents: %s", ErrPartialImport, len(failed), len(results), failed[0].Error)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	decoder := json.NewDecoder(export)
+	decoder.UseNumber()
+	for {
+		var document map[string]interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if document = transform(document); document == nil {
+			continue
+		}
+		batch = append(batch, document)
+		if len(batch) == batchSize {
+			if err := importBatch(); err != nil {
+				return err
+			}
+		}
+	}
+	return importBatch()
+}
+
+// ReindexStream builds a new collection with the new schema from the
+// documents received from docs and swaps the alias to it, e.g. to
+// rebuild a collection from its source of truth on every schema
+// change. It creates the new collection, imports the documents into it
+// like ImportDocumentsStream with the create action until docs is
+// closed, points the alias to the new collection and then deletes the
+// collection the alias pointed to, if any. The new collection is named
+// like in Reindex. When alias is the name of a collection rather than
+// an alias, that collection is deleted right before the alias is
+// created, like in Reindex.
+//
+// When the creation, any document of the import or the swap of the
+// alias fails, or ctx is cancelled, the new collection is deleted, the
+// alias and the collection it points to are left untouched and the
+// error is returned, joined with the error of the deletion if it
+// failed too. Once the alias is swapped the new collection is kept,
+// a failure to delete the previous collection is returned but leaves
+// the previous collection in place, with the alias already pointing to
+// the new one. The deletions are made even when ctx is cancelled. When
+// alias was a collection that was already deleted and the alias can't
+// be created, the new collection holds the only copy of the documents,
+// so it is kept and the returned error names it.
+func (c *Client) ReindexStream(ctx context.Context, alias string, newSchema CollectionSchema, docs <-chan interface{}, opts ...CallOption) error {
+	if newSchema.Name == "" || newSchema.Name == alias {
+		newSchema.Name = fmt.Sprintf("%s_%d", alias, time.Now().Unix())
+	}
+	opts = append(opts, WithContext(ctx))
+	previous, err := c.RetrieveAlias(alias, opts...)
+	if err != nil && err != ErrAliasNotFound {
+		return err
+	}
+	isCollection := false
+	if previous == nil {
+		_, err := c.RetrieveCollection(alias, opts...)
+		if err != nil && err != ErrCollectionNotFound {
+			return err
+		}
+		isCollection = err == nil
+	}
+	if _, err := c.CreateCollection(newSchema, opts...); err != nil {
+		return err
+	}
+	cleanup := func(err error) error {
+		return c.dropCollectionOnFailure(newSchema.Name, err, opts...)
+	}
+	results, err := c.ImportDocumentsStream(ctx, newSchema.Name, docs, "create", opts...)
+	if err != nil {
+		return cleanup(err)
+	}
+	var imported int
+	var failed []ImportResult
+	for result := range results {
+		imported++
+		if !result.Success {
+			failed = append(failed, result)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return cleanup(err)
+	}
+	if len(failed) > 0 {
+		return cleanup(fmt.Errorf("%w: %d of %d documents: %s", ErrPartialImport, len(failed), imported, failed[0].Error))
+	}
+	if isCollection {
+		if _, err := c.DeleteCollection(alias, opts...); err != nil {
+			return cleanup(err)
+		}
+		if _, err := c.UpsertAlias(alias, newSchema.Name, opts...); err != nil {
+			return fmt.Errorf("collection %s was deleted but the alias couldn't point to %s, which holds the documents: %w", alias, newSchema.Name, err)
+		}
+		return nil
+	}
+	if _, err := c.UpsertAlias(alias, newSchema.Name, opts...); err != nil {
+		return cleanup(err)
+	}
+	if previous == nil || previous.CollectionName == newSchema.Name {
+		return nil
+	}
+	_, err = c.DeleteCollection(previous.CollectionName, append(opts, WithContext(context.Background()))...)
+	return err
+}
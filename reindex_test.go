@@ -0,0 +1,234 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReindex(t *testing.T) {
+	var requests []string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		response := func(statusCode int, body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		}
+		switch req.Method + " " + req.URL.Path {
+		case "GET /aliases/companies":
+			return response(http.StatusNotFound, `{"message": "Not Found"}`)
+		case "POST /collections":
+			return response(http.StatusCreated, `{"name": "companies_v2", "num_documents": 0}`)
+		case "GET /collections/companies/documents/export":
+			return response(http.StatusOK, `{"id":"1","num_employees":"5215"}`+"\n"+`{"id":"2","num_employees":"10"}`)
+		case "POST /collections/companies_v2/documents/import":
+			body, _ := ioutil.ReadAll(req.Body)
+			expected := `{"id":"1","num_employees":5215}` + "\n" + `{"id":"2","num_employees":10}` + "\n"
+			if string(body) != expected {
+				t.Errorf("Expected to import %q, imported %q", expected, string(body))
+			}
+			return response(http.StatusOK, `{"success": true}`+"\n"+`{"success": true}`)
+		case "DELETE /collections/companies":
+			return response(http.StatusOK, `{"name": "companies"}`)
+		case "PUT /aliases/companies":
+			return response(http.StatusOK, `{"name": "companies", "collection_name": "companies_v2"}`)
+		}
+		t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+		return response(http.StatusNotFound, `{"message": "Not Found"}`)
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	newSchema := CollectionSchema{
+		Name:   "companies_v2",
+		Fields: []CollectionField{{Name: "num_employees", Type: "int32"}},
+	}
+	err := client.Reindex("companies", newSchema, func(document map[string]interface{}) map[string]interface{} {
+		document["num_employees"] = json.Number(document["num_employees"].(string))
+		return document
+	})
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected := []string{
+		"GET /aliases/companies",
+		"POST /collections",
+		"GET /collections/companies/documents/export",
+		"POST /collections/companies_v2/documents/import",
+		"DELETE /collections/companies",
+		"PUT /aliases/companies",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to make the requests %v, made %v", expected, requests)
+	}
+}
+
+func TestReindex_failure(t *testing.T) {
+	var requests []string
+	failing := "POST /collections/companies_v2/documents/import"
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		request := req.Method + " " + req.URL.Path
+		requests = append(requests, request)
+		response := func(statusCode int, body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		}
+		if request == failing {
+			return response(http.StatusBadRequest, `{"message": "Bad request."}`)
+		}
+		switch request {
+		case "GET /aliases/companies":
+			return response(http.StatusNotFound, `{"message": "Not Found"}`)
+		case "POST /collections":
+			return response(http.StatusCreated, `{"name": "companies_v2", "num_documents": 0}`)
+		case "GET /collections/companies/documents/export":
+			return response(http.StatusOK, `{"id":"1"}`)
+		case "POST /collections/companies_v2/documents/import":
+			return response(http.StatusOK, `{"success": true}`)
+		case "DELETE /collections/companies", "DELETE /collections/companies_v2":
+			return response(http.StatusOK, `{"name": "companies"}`)
+		}
+		t.Errorf("Unexpected request %s", request)
+		return response(http.StatusNotFound, `{"message": "Not Found"}`)
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	newSchema := CollectionSchema{
+		Name:   "companies_v2",
+		Fields: []CollectionField{{Name: "name", Type: "string"}},
+	}
+	identity := func(document map[string]interface{}) map[string]interface{} { return document }
+	if err := client.Reindex("companies", newSchema, identity); err == nil {
+		t.Fatalf("Expected the reindex to fail")
+	}
+	expected := []string{
+		"GET /aliases/companies",
+		"POST /collections",
+		"GET /collections/companies/documents/export",
+		"POST /collections/companies_v2/documents/import",
+		"DELETE /collections/companies_v2",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to make the requests %v, made %v", expected, requests)
+	}
+
+	requests = nil
+	failing = "PUT /aliases/companies"
+	err := client.Reindex("companies", newSchema, identity)
+	if err == nil || !strings.Contains(err.Error(), "companies_v2") {
+		t.Errorf("Expected the error to name the new collection, received %v", err)
+	}
+	if last := requests[len(requests)-1]; last != "PUT /aliases/companies" {
+		t.Errorf("Expected to keep the new collection once the collection is deleted, made %v", requests)
+	}
+}
+
+func TestReindexStream(t *testing.T) {
+	var requests []string
+	importResponse := `{"success": true}` + "\n" + `{"success": true}`
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		response := func(statusCode int, body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		}
+		switch req.Method + " " + req.URL.Path {
+		case "GET /aliases/companies":
+			return response(http.StatusOK, `{"name": "companies", "collection_name": "companies_v1"}`)
+		case "GET /aliases/products":
+			return response(http.StatusNotFound, `{"message": "Not Found"}`)
+		case "GET /collections/products":
+			return response(http.StatusOK, `{"name": "products", "num_documents": 2}`)
+		case "DELETE /collections/products":
+			return response(http.StatusOK, `{"name": "products"}`)
+		case "PUT /aliases/products":
+			return response(http.StatusOK, `{"name": "products", "collection_name": "companies_v2"}`)
+		case "POST /collections":
+			return response(http.StatusCreated, `{"name": "companies_v2", "num_documents": 0}`)
+		case "POST /collections/companies_v2/documents/import":
+			return response(http.StatusOK, importResponse)
+		case "PUT /aliases/companies":
+			return response(http.StatusOK, `{"name": "companies", "collection_name": "companies_v2"}`)
+		case "DELETE /collections/companies_v1":
+			return response(http.StatusOK, `{"name": "companies_v1"}`)
+		case "DELETE /collections/companies_v2":
+			return response(http.StatusOK, `{"name": "companies_v2"}`)
+		}
+		t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+		return response(http.StatusNotFound, `{"message": "Not Found"}`)
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	newSchema := CollectionSchema{
+		Name:   "companies_v2",
+		Fields: []CollectionField{{Name: "name", Type: "string"}},
+	}
+	documents := func() <-chan interface{} {
+		docs := make(chan interface{}, 2)
+		docs <- map[string]string{"id": "1", "name": "Stark Industries"}
+		docs <- map[string]string{"id": "2", "name": "Acme"}
+		close(docs)
+		return docs
+	}
+	if err := client.ReindexStream(context.Background(), "companies", newSchema, documents()); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected := []string{
+		"GET /aliases/companies",
+		"POST /collections",
+		"POST /collections/companies_v2/documents/import",
+		"PUT /aliases/companies",
+		"DELETE /collections/companies_v1",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to make the requests %v, made %v", expected, requests)
+	}
+
+	requests = nil
+	importResponse = `{"success": true}` + "\n" + `{"success": false, "error": "Field ` + "`name`" + ` must be a string."}`
+	err := client.ReindexStream(context.Background(), "companies", newSchema, documents())
+	if !errors.Is(err, ErrPartialImport) {
+		t.Errorf("Expected to receive error %v, received %v", ErrPartialImport, err)
+	}
+	expected = []string{
+		"GET /aliases/companies",
+		"POST /collections",
+		"POST /collections/companies_v2/documents/import",
+		"DELETE /collections/companies_v2",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to make the requests %v, made %v", expected, requests)
+	}
+	requests = nil
+	importResponse = `{"success": true}` + "\n" + `{"success": true}`
+	if err := client.ReindexStream(context.Background(), "products", newSchema, documents()); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	expected = []string{
+		"GET /aliases/products",
+		"GET /collections/products",
+		"POST /collections",
+		"POST /collections/companies_v2/documents/import",
+		"DELETE /collections/products",
+		"PUT /aliases/products",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected to replace the collection with the alias, made the requests %v", requests)
+	}
+}
@@ -0,0 +1,76 @@
+package typesense
+
+import "fmt"
+
+// RelevanceDiff is the difference between the rankings of two search
+// results, e.g. before and after a configuration change.
+type RelevanceDiff struct {
+	// Moved are the documents found in both results at a different rank.
+	Moved []RankChange
+
+	// Added are the ids of the documents only found in the second result.
+	Added []string
+
+	// Removed are the ids of the documents only found in the first result.
+	Removed []string
+}
+
+// RankChange is the change of rank of a document between two search
+// results, the ranks start at 1.
+type RankChange struct {
+	ID   string
+	From int
+	To   int
+}
+
+// Changed whether the rankings of the results differ.
+func (d RelevanceDiff) Changed() bool {
+	return len(d.Moved) > 0 || len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// CompareSearchResults compares the rankings of the hits of two search
+// results by the ids of their documents, e.g. for relevance regression
+// tests. The moved and added documents are in the order of the second
+// result and the removed documents in the order of the first one.
+func CompareSearchResults(a, b *SearchResponse) RelevanceDiff {
+	ranksA := hitRanks(a)
+	ranksB := hitRanks(b)
+	var diff RelevanceDiff
+	for _, hit := range searchHits(b) {
+		id := hitID(hit)
+		if from, ok := ranksA[id]; !ok {
+			diff.Added = append(diff.Added, id)
+		} else if to := ranksB[id]; from != to {
+			diff.Moved = append(diff.Moved, RankChange{ID: id, From: from, To: to})
+		}
+	}
+	for _, hit := range searchHits(a) {
+		if id := hitID(hit); ranksB[id] == 0 {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	return diff
+}
+
+func searchHits(result *SearchResponse) []SearchResultHit {
+	if result == nil {
+		return nil
+	}
+	return result.Hits
+}
+
+// hitRanks returns the ranks of the documents of the result by id.
+func hitRanks(result *SearchResponse) map[string]int {
+	hits := searchHits(result)
+	ranks := make(map[string]int, len(hits))
+	for i, hit := range hits {
+		if id := hitID(hit); ranks[id] == 0 {
+			ranks[id] = i + 1
+		}
+	}
+	return ranks
+}
+
+func hitID(hit SearchResultHit) string {
+	return fmt.Sprint(hit.Document["id"])
+}
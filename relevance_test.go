@@ -0,0 +1,34 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testSearchResponse(ids ...string) *SearchResponse {
+	result := SearchResponse{Found: len(ids)}
+	for _, id := range ids {
+		result.Hits = append(result.Hits, SearchResultHit{Document: map[string]interface{}{"id": id}})
+	}
+	return &result
+}
+
+func TestCompareSearchResults(t *testing.T) {
+	before := testSearchResponse("1", "2", "3", "4")
+	after := testSearchResponse("2", "1", "3", "5")
+	diff := CompareSearchResults(before, after)
+	expected := RelevanceDiff{
+		Moved:   []RankChange{{ID: "2", From: 2, To: 1}, {ID: "1", From: 1, To: 2}},
+		Added:   []string{"5"},
+		Removed: []string{"4"},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected to receive diff %+v, received %+v", expected, diff)
+	}
+	if !diff.Changed() {
+		t.Errorf("Expected the rankings to have changed")
+	}
+	if diff := CompareSearchResults(before, testSearchResponse("1", "2", "3", "4")); diff.Changed() {
+		t.Errorf("Expected the same rankings to not have changed, received %+v", diff)
+	}
+}
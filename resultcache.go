@@ -0,0 +1,148 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ResultCache stores the results of the searches of the client so they
+// can be served while Typesense is down, see WithResultCache. The
+// implementations must be safe for concurrent use.
+type ResultCache interface {
+	// Get returns the result stored with the key, if any.
+	Get(key string) (*SearchResponse, bool)
+	// Set stores the result with the key, replacing any previous one.
+	Set(key string, result *SearchResponse)
+}
+
+// WithResultCache degrades the searches of the client gracefully during
+// outages. The result of every successful search is stored in the cache
+// and, when a search fails with a transport or server error and the
+// node fails its health check, the cached result of the same search is
+// returned instead of the error. Searches that fail while the node is
+// healthy, e.g. with a bad request, and searches canceled by their
+// context still return their error.
+func WithResultCache(cache ResultCache) ClientOption {
+	return func(c *Client) {
+		c.resultCache = cache
+	}
+}
+
+// SearchCacheKey returns the key of the search in a ResultCache, built
+// from the collection name and the encoded search parameters. The
+// client stores the results with the parameters it sends, i.e. with its
+// defaults such as WithDefaultQueryBy applied. It is empty for invalid
+// parameters.
+func SearchCacheKey(collectionName string, params SearchParameters) string {
+	form, err := params.encodeForm()
+	if err != nil {
+		return ""
+	}
+	return collectionName + "?" + form
+}
+
+// cacheResult stores a copy of the result of the search in the result
+// cache of the client, if it has one.
+func (c *Client) cacheResult(collectionName string, params SearchParameters, result *SearchResponse, opts []CallOption) {
+	if !c.cacheable(opts) {
+		return
+	}
+	if key := SearchCacheKey(collectionName, c.searchDefaults(params)); key != "" {
+		c.resultCache.Set(key, result.clone())
+	}
+}
+
+// cachedResult returns a copy of the cached result of a search that
+// failed with err when the client has a result cache, the search failed
+// because of an outage and the node fails its health check.
+func (c *Client) cachedResult(collectionName string, params SearchParameters, err error, opts []CallOption) (*SearchResponse, bool) {
+	if !c.cacheable(opts) || !outageError(err) {
+		return nil, false
+	}
+	key := SearchCacheKey(collectionName, c.searchDefaults(params))
+	if key == "" {
+		return nil, false
+	}
+	if healthy, err := c.Health(opts...); err == nil && healthy {
+		return nil, false
+	}
+	result, ok := c.resultCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return result.clone(), true
+}
+
+// outageError reports whether the search failed because Typesense could
+// not be reached or answered with a server error. A search canceled or
+// timed out by the context of the caller is not an outage.
+func outageError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var transportErr *url.Error
+	return errors.As(err, &transportErr)
+}
+
+// cacheable reports whether the client has a result cache and the
+// search is made with the API key of the client. The results of
+// searches made with another key, e.g. a scoped key, would be served to
+// the searches of other keys.
+func (c *Client) cacheable(opts []CallOption) bool {
+	return c.resultCache != nil && c.newCallOptions(opts).apiKey == ""
+}
+
+// clone returns a copy of the response that shares none of its hits,
+// facets or documents, so the callers served from the cache can modify
+// their results without affecting each other.
+func (r *SearchResponse) clone() *SearchResponse {
+	clone := *r
+	clone.FacetCounts = append([]FacetCount(nil), r.FacetCounts...)
+	for i := range clone.FacetCounts {
+		clone.FacetCounts[i].Counts = append([]FacetValue(nil), r.FacetCounts[i].Counts...)
+	}
+	clone.Hits = cloneHits(r.Hits)
+	clone.GroupedHits = append([]GroupedHit(nil), r.GroupedHits...)
+	for i := range clone.GroupedHits {
+		clone.GroupedHits[i].GroupKey = append([]interface{}(nil), r.GroupedHits[i].GroupKey...)
+		clone.GroupedHits[i].Hits = cloneHits(r.GroupedHits[i].Hits)
+	}
+	if r.Conversation != nil {
+		conversation := *r.Conversation
+		clone.Conversation = &conversation
+	}
+	return &clone
+}
+
+func cloneHits(hits []SearchResultHit) []SearchResultHit {
+	clone := append([]SearchResultHit(nil), hits...)
+	for i, hit := range clone {
+		hit.Highlights = append([]SearchHighlight(nil), hit.Highlights...)
+		if hit.Document != nil {
+			document := make(map[string]interface{}, len(hit.Document))
+			for field, value := range hit.Document {
+				document[field] = value
+			}
+			hit.Document = document
+		}
+		if hit.GeoDistanceMeters != nil {
+			distances := make(map[string]int, len(hit.GeoDistanceMeters))
+			for field, distance := range hit.GeoDistanceMeters {
+				distances[field] = distance
+			}
+			hit.GeoDistanceMeters = distances
+		}
+		if hit.TextMatchInfo != nil {
+			info := *hit.TextMatchInfo
+			hit.TextMatchInfo = &info
+		}
+		clone[i] = hit
+	}
+	return clone
+}
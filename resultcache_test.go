@@ -0,0 +1,202 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testResultCache struct {
+	mu      sync.Mutex
+	results map[string]*SearchResponse
+}
+
+func (c *testResultCache) Get(key string) (*SearchResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.results[key]
+	return result, ok
+}
+
+func (c *testResultCache) Set(key string, result *SearchResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = result
+}
+
+func TestSearch_resultCache(t *testing.T) {
+	nodeDown := false
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if nodeDown {
+			return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: errors.New("connection refused")}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	cache := &testResultCache{results: make(map[string]*SearchResponse)}
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		resultCache: cache,
+	}
+	params := SearchParameters{Q: "harry", QueryBy: []string{"title"}}
+	searchResp, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	if cached, ok := cache.Get(SearchCacheKey("books", params)); !ok || cached == searchResp || cached.Found != searchResp.Found {
+		t.Errorf("Expected a copy of the result to be cached, received %v", cached)
+	}
+	nodeDown = true
+	cachedResp, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive the cached result, received error %v", err)
+	}
+	if cachedResp.Found != searchResp.Found || len(cachedResp.Hits) != len(searchResp.Hits) {
+		t.Errorf("Expected to receive the cached result %v, received %v", searchResp, cachedResp)
+	}
+	params.Q = "potter"
+	if _, err := client.Search("books", params); err == nil {
+		t.Errorf("Expected to receive an error for a search that was not cached")
+	}
+}
+
+func TestSearch_resultCacheHealthyNode(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/health" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"ok": true}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Could not find a field named ` + "`titl`" + ` in the schema."}`)),
+		}, nil
+	}
+	params := SearchParameters{Q: "harry", QueryBy: []string{"titl"}}
+	cache := &testResultCache{results: map[string]*SearchResponse{SearchCacheKey("books", params): {Found: 1}}}
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		resultCache: cache,
+	}
+	var apiErr *APIError
+	if _, err := client.Search("books", params); !errors.As(err, &apiErr) {
+		t.Errorf("Expected to receive the API error while the node is healthy, received %v", err)
+	}
+}
+
+func TestSearch_resultCacheCopies(t *testing.T) {
+	nodeDown := false
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if nodeDown {
+			return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: errors.New("connection refused")}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		resultCache: &testResultCache{results: make(map[string]*SearchResponse)},
+	}
+	params := SearchParameters{Q: "harry", QueryBy: []string{"title"}}
+	searchResp, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	title := searchResp.Hits[0].Document["title"]
+	searchResp.Hits[0].Document["title"] = "changed"
+	nodeDown = true
+	first, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive the cached result, received error %v", err)
+	}
+	first.Hits[0].Document["title"] = "changed"
+	first.Hits = first.Hits[:0]
+	second, err := client.Search("books", params)
+	if err != nil {
+		t.Fatalf("Expected to receive the cached result, received error %v", err)
+	}
+	if len(second.Hits) == 0 || second.Hits[0].Document["title"] != title {
+		t.Errorf("Expected the cached result to be unaffected by the callers, received %v", second.Hits)
+	}
+}
+
+func TestSearch_resultCacheClientDefaults(t *testing.T) {
+	nodeDown := false
+	var healthHeader string
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/health" {
+			healthHeader = req.Header.Get("X-Request-Id")
+		}
+		if nodeDown {
+			return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: errors.New("connection refused")}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	cache := &testResultCache{results: make(map[string]*SearchResponse)}
+	client := Client{
+		httpClient:     mockClient,
+		masterNode:     testMasterNode,
+		resultCache:    cache,
+		defaultQueryBy: []string{"title"},
+	}
+	params := SearchParameters{Q: "harry"}
+	if _, err := client.Search("books", params); err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	key := SearchCacheKey("books", SearchParameters{Q: "harry", QueryBy: []string{"title"}})
+	if _, ok := cache.Get(key); !ok {
+		t.Errorf("Expected the result to be cached with the default query by fields, received %v", cache.results)
+	}
+	nodeDown = true
+	if _, err := client.Search("books", params, WithHeader("X-Request-Id", "42")); err != nil {
+		t.Errorf("Expected to receive the cached result, received error %v", err)
+	}
+	if healthHeader != "42" {
+		t.Errorf("Expected the health check to be made with the options of the search, received header %q", healthHeader)
+	}
+}
+
+func TestSearch_resultCacheOutagesOnly(t *testing.T) {
+	var statusCode int
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: err}
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Ready or Lagging"}`)),
+		}, nil
+	}
+	params := SearchParameters{Q: "harry", QueryBy: []string{"title"}}
+	cache := &testResultCache{results: map[string]*SearchResponse{SearchCacheKey("books", params): {Found: 1}}}
+	client := Client{
+		httpClient:  mockClient,
+		masterNode:  testMasterNode,
+		resultCache: cache,
+	}
+	statusCode = http.StatusServiceUnavailable
+	if searchResp, err := client.Search("books", params); err != nil || searchResp.Found != 1 {
+		t.Errorf("Expected to receive the cached result while the node is unavailable, received %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Search("books", params, WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected to receive error %v for a canceled search, received %v", context.Canceled, err)
+	}
+}
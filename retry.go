@@ -0,0 +1,134 @@
+package typesense
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// retryableMethods are the methods retried by the client, the requests
+// with other methods, e.g. POST to index documents, are never retried
+// to avoid duplicate writes.
+var retryableMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+}
+
+// WithRetry retries the idempotent requests (GET, PUT and DELETE) that
+// failed with a transport error or with a 429 or 503 status, up to
+// maxAttempts attempts in total. The delay between attempts starts at
+// baseDelay and doubles after each attempt, a Retry-After header of a
+// 429 or 503 response is honored instead, up to a minute. The last
+// error or response is returned once the attempts are exhausted.
+func WithRetry(maxAttempts int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryMaxAttempts = maxAttempts
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// RetryStats are the counters of the retries made by the client.
+type RetryStats struct {
+	// Retries is the number of requests retried.
+	Retries int64
+
+	// RateLimitWaits is the number of retries made after waiting for a
+	// rate limited (429) response.
+	RateLimitWaits int64
+}
+
+// RetryStats returns the counters of the retries made by the client
+// since it was created, it is safe to call concurrently with requests.
+func (c *Client) RetryStats() RetryStats {
+	return RetryStats{
+		Retries:        atomic.LoadInt64(&c.retries),
+		RateLimitWaits: atomic.LoadInt64(&c.rateLimitWaits),
+	}
+}
+
+// countRetry updates the retry counters before retrying after the
+// given response, which is nil for transport errors.
+func (c *Client) countRetry(resp *http.Response) {
+	atomic.AddInt64(&c.retries, 1)
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		atomic.AddInt64(&c.rateLimitWaits, 1)
+	}
+}
+
+// maxAttempts returns the number of attempts of a request with the
+// given method.
+func (c *Client) maxAttempts(method string) int {
+	if c.retryMaxAttempts > 1 && retryableMethods[method] {
+		return c.retryMaxAttempts
+	}
+	return 1
+}
+
+// shouldRetry whether the attempt failed with a transient error.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// maxRetryAfter is the longest delay of a Retry-After header honored
+// before retrying, so a single response can't block a call for hours.
+const maxRetryAfter = time.Minute
+
+// retryDelay returns the delay before the next attempt, after the
+// given number of failed attempts.
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if delay > maxRetryAfter {
+				return maxRetryAfter
+			}
+			return delay
+		}
+	}
+	return c.retryBaseDelay << (attempt - 1)
+}
+
+// parseRetryAfter parses a Retry-After header, either in seconds or
+// as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// tooManyRequestsError returns the error of a rate limited response, a
+// *RateLimitError when it has a Retry-After header.
+func tooManyRequestsError(resp *http.Response) error {
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return &RateLimitError{retryAfter}
+	}
+	return ErrTooManyRequests
+}
+
+// sleepContext waits for the delay, it returns false if the context
+// is done before.
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,182 @@
+package typesense
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	attempts := 0
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		} else if attempts == 2 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Ready or Lagging"}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		retryMaxAttempts: 3,
+		retryBaseDelay:   time.Millisecond,
+	}
+	if _, err := client.Search(collectionNameTest, SearchParameters{Q: "query", QueryBy: []string{"title"}}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected to make %d attempts, made %d", 3, attempts)
+	}
+}
+
+func TestWithRetry_exhausted(t *testing.T) {
+	attempts := 0
+	transportErr := errors.New("connection refused")
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, transportErr
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		retryMaxAttempts: 3,
+		retryBaseDelay:   time.Millisecond,
+	}
+	if _, err := client.RetrieveCollections(); err != transportErr {
+		t.Errorf("Expected to receive error %v, received %v", transportErr, err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected to make %d attempts, made %d", 3, attempts)
+	}
+}
+
+func TestWithRetry_postNotRetried(t *testing.T) {
+	attempts := 0
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Not Ready or Lagging"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		retryMaxAttempts: 3,
+		retryBaseDelay:   time.Millisecond,
+	}
+	client.IndexDocument(collectionNameTest, testDocument)
+	if attempts != 1 {
+		t.Errorf("Expected to make %d attempt, made %d", 1, attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if delay, ok := parseRetryAfter("2"); !ok || delay != 2*time.Second {
+		t.Errorf("Expected to parse a delay of %v, parsed %v", 2*time.Second, delay)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := parseRetryAfter(date); !ok || delay <= 0 || delay > time.Minute {
+		t.Errorf("Expected to parse a delay of up to a minute, parsed %v", delay)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Errorf("Expected to not parse an invalid Retry-After")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := Client{retryBaseDelay: 100 * time.Millisecond}
+	tests := []struct {
+		retryAfter string
+		expected   time.Duration
+	}{
+		{"", 400 * time.Millisecond},
+		{"7", 7 * time.Second},
+		{"3600", maxRetryAfter},
+	}
+	for _, test := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if test.retryAfter != "" {
+			resp.Header.Set("Retry-After", test.retryAfter)
+		}
+		if delay := client.retryDelay(3, resp); delay != test.expected {
+			t.Errorf("Expected a delay of %v for Retry-After %q, received %v", test.expected, test.retryAfter, delay)
+		}
+	}
+}
+
+func TestRetryStats(t *testing.T) {
+	attempts := 0
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Rate limit exceeded"}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(searchResultTest)),
+		}, nil
+	}
+	client := Client{
+		httpClient:       mockClient,
+		masterNode:       testMasterNode,
+		retryMaxAttempts: 3,
+		retryBaseDelay:   time.Millisecond,
+	}
+	if _, err := client.Search(collectionNameTest, SearchParameters{Q: "query", QueryBy: []string{"title"}}); err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := RetryStats{Retries: 1, RateLimitWaits: 1}
+	if stats := client.RetryStats(); stats != expected {
+		t.Errorf("Expected to receive retry stats %+v, received %+v", expected, stats)
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	tests := []struct {
+		header     http.Header
+		retryAfter time.Duration
+	}{
+		{http.Header{"Retry-After": []string{"7"}}, 7 * time.Second},
+		{http.Header{}, 0},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     test.header,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Rate limit exceeded"}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		_, err := client.ImportDocuments(collectionNameTest, []interface{}{testDocument}, "create")
+		if !errors.Is(err, ErrTooManyRequests) {
+			t.Errorf("Expected to receive error %v, received %v", ErrTooManyRequests, err)
+		}
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) != (test.retryAfter > 0) {
+			t.Errorf("Expected to receive a *RateLimitError only with a Retry-After header, received %v", err)
+		} else if rateLimitErr != nil && rateLimitErr.RetryAfter() != test.retryAfter {
+			t.Errorf("Expected to retry after %v, received %v", test.retryAfter, rateLimitErr.RetryAfter())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package typesense
+
+import "reflect"
+
+// FieldChangeKind is the kind of change of a field between two
+// collection schemas.
+type FieldChangeKind int
+
+const (
+	// FieldAdded is a field only declared by the second schema.
+	FieldAdded FieldChangeKind = iota
+
+	// FieldDropped is a field only declared by the first schema.
+	FieldDropped
+
+	// FieldChanged is a field declared by both schemas with different
+	// definitions, e.g. a different type.
+	FieldChanged
+)
+
+// String returns the name of the kind of change.
+func (k FieldChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "added"
+	case FieldDropped:
+		return "dropped"
+	case FieldChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// FieldChange is the change of a field between two collection schemas.
+// Old is nil for an added field and New is nil for a dropped field.
+type FieldChange struct {
+	Name string
+	Kind FieldChangeKind
+	Old  *CollectionField
+	New  *CollectionField
+}
+
+// TypeChanged whether the type of the changed field differs.
+func (c FieldChange) TypeChanged() bool {
+	return c.Kind == FieldChanged && c.Old.Type != c.New.Type
+}
+
+// SchemaDiff compares the fields of two collection schemas by their
+// names, e.g. to migrate a collection from the schema a to the schema
+// b. The dropped and changed fields are in the order of a, followed by
+// the added fields in the order of b. The options left unset are
+// compared as the defaults Typesense applies, so a schema retrieved
+// with RetrieveSchema matches the schema it was created with. The
+// other settings of the schemas are not compared.
+func SchemaDiff(a, b CollectionSchema) []FieldChange {
+	fieldsA := make(map[string]*CollectionField, len(a.Fields))
+	for i := range a.Fields {
+		fieldsA[a.Fields[i].Name] = &a.Fields[i]
+	}
+	fieldsB := make(map[string]*CollectionField, len(b.Fields))
+	for i := range b.Fields {
+		fieldsB[b.Fields[i].Name] = &b.Fields[i]
+	}
+	var changes []FieldChange
+	for i := range a.Fields {
+		fieldA := &a.Fields[i]
+		fieldB, ok := fieldsB[fieldA.Name]
+		if !ok {
+			changes = append(changes, FieldChange{Name: fieldA.Name, Kind: FieldDropped, Old: fieldA})
+		} else if !reflect.DeepEqual(normalizedField(*fieldA), normalizedField(*fieldB)) {
+			changes = append(changes, FieldChange{Name: fieldA.Name, Kind: FieldChanged, Old: fieldA, New: fieldB})
+		}
+	}
+	for i := range b.Fields {
+		if _, ok := fieldsA[b.Fields[i].Name]; !ok {
+			changes = append(changes, FieldChange{Name: b.Fields[i].Name, Kind: FieldAdded, New: &b.Fields[i]})
+		}
+	}
+	return changes
+}
+
+// normalizedField returns the field with the options left unset
+// replaced by the defaults Typesense applies and returns them with.
+func normalizedField(field CollectionField) CollectionField {
+	if field.Index == nil {
+		index := true
+		field.Index = &index
+	}
+	if field.Stem == nil {
+		stem := false
+		field.Stem = &stem
+	}
+	if sortingFieldTypes[field.Type] {
+		field.Sort = true
+	}
+	return field
+}
+
+// SchemaUpdates returns the field updates of UpdateCollection that
+// apply the changes, a changed field is dropped and added again in the
+// same update.
+func SchemaUpdates(changes []FieldChange) []CollectionFieldUpdate {
+	var updates []CollectionFieldUpdate
+	for _, change := range changes {
+		if change.Kind == FieldDropped || change.Kind == FieldChanged {
+			updates = append(updates, CollectionFieldUpdate{CollectionField: CollectionField{Name: change.Name}, Drop: true})
+		}
+		if change.Kind == FieldAdded || change.Kind == FieldChanged {
+			updates = append(updates, CollectionFieldUpdate{CollectionField: *change.New})
+		}
+	}
+	return updates
+}
@@ -0,0 +1,85 @@
+package typesense
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	a := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "num_employees", Type: "int32"},
+			{Name: "country", Type: "string", Facet: true},
+			{Name: "founded", Type: "int32"},
+		},
+	}
+	b := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "num_employees", Type: "int64"},
+			{Name: "country", Type: "string"},
+			{Name: "website", Type: "string", Optional: true},
+		},
+	}
+	changes := SchemaDiff(a, b)
+	expected := []FieldChange{
+		{Name: "num_employees", Kind: FieldChanged, Old: &a.Fields[1], New: &b.Fields[1]},
+		{Name: "country", Kind: FieldChanged, Old: &a.Fields[2], New: &b.Fields[2]},
+		{Name: "founded", Kind: FieldDropped, Old: &a.Fields[3]},
+		{Name: "website", Kind: FieldAdded, New: &b.Fields[3]},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Expected to receive the changes %+v, received %+v", expected, changes)
+	}
+	if !changes[0].TypeChanged() || changes[1].TypeChanged() {
+		t.Errorf("Expected only the type of num_employees to change")
+	}
+	updates := SchemaUpdates(changes)
+	expectedUpdates := []CollectionFieldUpdate{
+		{CollectionField: CollectionField{Name: "num_employees"}, Drop: true},
+		{CollectionField: b.Fields[1]},
+		{CollectionField: CollectionField{Name: "country"}, Drop: true},
+		{CollectionField: b.Fields[2]},
+		{CollectionField: CollectionField{Name: "founded"}, Drop: true},
+		{CollectionField: b.Fields[3]},
+	}
+	if !reflect.DeepEqual(updates, expectedUpdates) {
+		t.Errorf("Expected to receive the updates %+v, received %+v", expectedUpdates, updates)
+	}
+	if changes := SchemaDiff(a, a.Clone()); len(changes) != 0 {
+		t.Errorf("Expected no changes between equal schemas, received %+v", changes)
+	}
+}
+
+func TestSchemaDiff_serverDefaults(t *testing.T) {
+	var retrieved CollectionSchema
+	err := json.Unmarshal([]byte(`{
+		"name": "companies",
+		"fields": [
+			{"name": "name", "type": "string", "facet": false, "index": true, "optional": false, "sort": false, "infix": false, "locale": "", "stem": false},
+			{"name": "num_employees", "type": "int32", "facet": true, "index": true, "optional": false, "sort": true, "infix": false, "locale": "", "stem": false}
+		]
+	}`), &retrieved)
+	if err != nil {
+		t.Fatalf("Expected to receive no errors, received %v", err)
+	}
+	written := CollectionSchema{
+		Name: "companies",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string"},
+			{Name: "num_employees", Type: "int32", Facet: true},
+		},
+	}
+	if changes := SchemaDiff(retrieved, written); len(changes) != 0 {
+		t.Errorf("Expected no changes between the retrieved and the written schema, received %+v", changes)
+	}
+	index := false
+	written.Fields[0].Index = &index
+	if changes := SchemaDiff(retrieved, written); len(changes) != 1 || changes[0].Name != "name" {
+		t.Errorf("Expected the index of name to change, received %+v", changes)
+	}
+}
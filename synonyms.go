@@ -0,0 +1,152 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const synonymsEndpoint = "synonyms"
+
+// Synonym is a Typesense synonym of a collection. Without a root the
+// synonyms are multi-way, all words are synonyms of each other, with
+// a root the synonyms are one-way, the root is a synonym of the words
+// but the words are not synonyms of the root.
+type Synonym struct {
+	ID       string   `json:"id"`
+	Root     string   `json:"root,omitempty"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// UpsertSynonym creates the synonym of the collection or replaces it
+// if it already exists.
+func (c *Client) UpsertSynonym(collectionName, synonymID string, synonym Synonym, opts ...CallOption) (*Synonym, error) {
+	method := http.MethodPut
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		synonymsEndpoint,
+		synonymID,
+	)
+	synonym.ID = synonymID
+	synonymJSON, _ := json.Marshal(synonym)
+	resp, err := c.apiCall(method, url, synonymJSON, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var synonymResponse Synonym
+	if err := json.NewDecoder(resp.Body).Decode(&synonymResponse); err != nil {
+		return nil, err
+	}
+	return &synonymResponse, nil
+}
+
+// RetrieveSynonym retrieves a single synonym of the collection by its id.
+func (c *Client) RetrieveSynonym(collectionName, synonymID string, opts ...CallOption) (*Synonym, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		synonymsEndpoint,
+		synonymID,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resourceNotFoundError(resp.Body, ErrSynonymNotFound)
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var synonym Synonym
+	if err := json.NewDecoder(resp.Body).Decode(&synonym); err != nil {
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+// RetrieveSynonyms retrieves all synonyms of the collection.
+func (c *Client) RetrieveSynonyms(collectionName string, opts ...CallOption) ([]*Synonym, error) {
+	method := http.MethodGet
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		synonymsEndpoint,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCollectionNotFound
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	type synonymsResponse struct {
+		Synonyms []*Synonym `json:"synonyms"`
+	}
+	var synonyms synonymsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&synonyms); err != nil {
+		return nil, err
+	}
+	return synonyms.Synonyms, nil
+}
+
+// DeleteSynonym deletes a synonym of the collection by its id.
+func (c *Client) DeleteSynonym(collectionName, synonymID string, opts ...CallOption) (*Synonym, error) {
+	method := http.MethodDelete
+	url := fmt.Sprintf(
+		"%s://%s:%s/%s/%s/%s/%s",
+		c.masterNode.Protocol,
+		c.masterNode.Host,
+		c.masterNode.Port,
+		collectionsEndpoint,
+		collectionName,
+		synonymsEndpoint,
+		synonymID,
+	)
+	resp, err := c.apiCall(method, url, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resourceNotFoundError(resp.Body, ErrSynonymNotFound)
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp)
+	}
+	var synonym Synonym
+	if err := json.NewDecoder(resp.Body).Decode(&synonym); err != nil {
+		return nil, err
+	}
+	return &synonym, nil
+}
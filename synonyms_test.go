@@ -0,0 +1,117 @@
+package typesense
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSynonym_oneWay(t *testing.T) {
+	synonymJSON, _ := json.Marshal(Synonym{ID: "smart-phone", Synonyms: []string{"iphone", "android"}})
+	if strings.Contains(string(synonymJSON), "root") {
+		t.Errorf("Expected to omit the root of multi-way synonyms, marshalled %s", synonymJSON)
+	}
+	synonymJSON, _ = json.Marshal(Synonym{ID: "smart-phone", Root: "smart phone", Synonyms: []string{"iphone"}})
+	if !strings.Contains(string(synonymJSON), `"root":"smart phone"`) {
+		t.Errorf("Expected to marshal the root of one-way synonyms, marshalled %s", synonymJSON)
+	}
+}
+
+func TestUpsertSynonym(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodPut, req.Method)
+		}
+		if req.URL.Path != "/collections/products/synonyms/coat-synonyms" {
+			t.Errorf("Expected to request path %s, requested %s", "/collections/products/synonyms/coat-synonyms", req.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(string(body))),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	synonym, err := client.UpsertSynonym("products", "coat-synonyms", Synonym{Synonyms: []string{"blazer", "coat", "jacket"}})
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if synonym.ID != "coat-synonyms" || len(synonym.Synonyms) != 3 {
+		t.Errorf("Expected to receive the upserted synonym, received %+v", synonym)
+	}
+}
+
+func TestRetrieveSynonym_notFound(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected error
+	}{
+		{"Could not find that `id`.", ErrSynonymNotFound},
+		{"Collection not found", ErrCollectionNotFound},
+	}
+	for _, test := range tests {
+		mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "` + test.message + `"}`)),
+			}, nil
+		}
+		client := Client{
+			httpClient: mockClient,
+			masterNode: testMasterNode,
+		}
+		if _, err := client.RetrieveSynonym("products", "coat-synonyms"); err != test.expected {
+			t.Errorf("Expected to receive error %v, received %v", test.expected, err)
+		}
+	}
+}
+
+func TestRetrieveSynonyms(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"synonyms": [{"id": "coat-synonyms", "synonyms": ["blazer", "coat"]}, {"id": "smart-phone", "root": "smart phone", "synonyms": ["iphone"]}]}`,
+			)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	synonyms, err := client.RetrieveSynonyms("products")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if len(synonyms) != 2 || synonyms[1].Root != "smart phone" {
+		t.Errorf("Expected to receive two synonyms, received %v", synonyms)
+	}
+}
+
+func TestDeleteSynonym(t *testing.T) {
+	mockClient.DoFunc = func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete {
+			t.Errorf("Expected to receive method %s, received %s", http.MethodDelete, req.Method)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id": "coat-synonyms"}`)),
+		}, nil
+	}
+	client := Client{
+		httpClient: mockClient,
+		masterNode: testMasterNode,
+	}
+	synonym, err := client.DeleteSynonym("products", "coat-synonyms")
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	if synonym.ID != "coat-synonyms" {
+		t.Errorf("Expected to receive synonym %s, received %s", "coat-synonyms", synonym.ID)
+	}
+}
@@ -0,0 +1,54 @@
+package typesense
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VectorQueryBuilder builds the vector_query search option to search
+// for the nearest neighbors of a vector, e.g.
+// embedding:([0.1, 0.2], k:10, distance_threshold:0.3). Combine it
+// with the FilterBy search option to pre-filter the neighbors.
+type VectorQueryBuilder struct {
+	field             string
+	vector            []float32
+	k                 int
+	distanceThreshold *float64
+}
+
+// NewVectorQuery creates a builder to query the k nearest neighbors
+// of the vector in the field.
+func NewVectorQuery(field string, vector []float32, k int) *VectorQueryBuilder {
+	return &VectorQueryBuilder{
+		field:  field,
+		vector: vector,
+		k:      k,
+	}
+}
+
+// DistanceThreshold sets the maximum distance of the neighbors to the
+// queried vector.
+func (b *VectorQueryBuilder) DistanceThreshold(threshold float64) *VectorQueryBuilder {
+	b.distanceThreshold = &threshold
+	return b
+}
+
+// Build returns the vector_query string. It returns ErrVectorRequired
+// if the vector is empty and ErrInvalidVectorK if k is not positive.
+func (b *VectorQueryBuilder) Build() (string, error) {
+	if len(b.vector) == 0 {
+		return "", ErrVectorRequired
+	} else if b.k <= 0 {
+		return "", ErrInvalidVectorK
+	}
+	values := make([]string, len(b.vector))
+	for i, value := range b.vector {
+		values[i] = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	}
+	params := []string{fmt.Sprintf("k:%d", b.k)}
+	if b.distanceThreshold != nil {
+		params = append(params, "distance_threshold:"+strconv.FormatFloat(*b.distanceThreshold, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s:([%s], %s)", b.field, strings.Join(values, ","), strings.Join(params, ", ")), nil
+}
@@ -0,0 +1,25 @@
+package typesense
+
+import "testing"
+
+func TestVectorQueryBuilder(t *testing.T) {
+	vectorQuery, err := NewVectorQuery("embedding", []float32{0.1, 0.25, -1}, 10).
+		DistanceThreshold(0.3).
+		Build()
+	if err != nil {
+		t.Errorf("Expected to receive no errors, received %v", err)
+	}
+	expected := "embedding:([0.1,0.25,-1], k:10, distance_threshold:0.3)"
+	if vectorQuery != expected {
+		t.Errorf("Expected to receive %v, received %v", expected, vectorQuery)
+	}
+}
+
+func TestVectorQueryBuilder_invalid(t *testing.T) {
+	if _, err := NewVectorQuery("embedding", nil, 10).Build(); err != ErrVectorRequired {
+		t.Errorf("Expected to receive error %v, received %v", ErrVectorRequired, err)
+	}
+	if _, err := NewVectorQuery("embedding", []float32{0.1}, 0).Build(); err != ErrInvalidVectorK {
+		t.Errorf("Expected to receive error %v, received %v", ErrInvalidVectorK, err)
+	}
+}